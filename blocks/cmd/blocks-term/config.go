@@ -0,0 +1,112 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pierrec/games/blocks/internal/colors"
+	"github.com/pierrec/games/blocks/internal/engine"
+)
+
+// configFile is the config of the graphical front end, in the user config directory
+// where it saves it on desktops. Its block color and key bindings are shared.
+const configFile = "blocks.cfg"
+
+// config holds the settings of the graphical front end used by the terminal.
+type config struct {
+	Keys []struct {
+		Key string `json:"key"`
+	} `json:"Keys"`
+	BlockColor uint16 `json:"blockcolor"`
+}
+
+// keyOrder lists the actions of the keys in the order of the graphical front end keymap,
+// engine.None being the pause key.
+var keyOrder = [...]engine.Action{
+	engine.MoveLeft,
+	engine.MoveRight,
+	engine.DropHard,
+	engine.DropSoft,
+	engine.RotateLeft,
+	engine.RotateRight,
+	engine.None,
+	engine.Hold,
+}
+
+// loadConfig applies the settings of the graphical front end, if it was ever run.
+func loadConfig() error {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		// No config to share.
+		return nil
+	}
+	fName := filepath.Join(dir, configFile)
+	bts, err := os.ReadFile(fName)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	var cfg config
+	if err := json.Unmarshal(bts, &cfg); err != nil {
+		return fmt.Errorf("%s: %w", fName, err)
+	}
+	cfg.apply()
+	return nil
+}
+
+// apply sets the block color and the key bindings from the config.
+func (cfg *config) apply() {
+	if c, ok := colors.Texture(cfg.BlockColor); ok {
+		blockColor = c
+	}
+	if len(cfg.Keys) == 0 {
+		return
+	}
+	keyActions = make(map[string]engine.Action)
+	keyPause = ""
+	for i, k := range cfg.Keys {
+		if i == len(keyOrder) {
+			break
+		}
+		name := keyName(k.Key)
+		switch {
+		case name == "":
+		case keyOrder[i] == engine.None:
+			keyPause = name
+		default:
+			keyActions[name] = keyOrder[i]
+		}
+	}
+}
+
+// keyName returns the name of the key as reported by readKeys, given its
+// name in the graphical front end, or "" if the terminal does not report it.
+func keyName(name string) string {
+	switch name {
+	case "←":
+		return keyLeft
+	case "→":
+		return keyRight
+	case "↑":
+		return keyUp
+	case "↓":
+		return keyDown
+	case "⎋":
+		return keyEscape
+	case "Space":
+		return " "
+	case "⇥":
+		return "\t"
+	case "⏎":
+		return "\r"
+	}
+	if len(name) == 1 && name[0] > ' ' && name[0] < 0x7f {
+		return strings.ToLower(name)
+	}
+	return ""
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"io"
+	"strings"
+
+	"github.com/pierrec/games/blocks/internal/engine"
+)
+
+// Key names, as reported by readKeys.
+const (
+	keyLeft   = "left"
+	keyRight  = "right"
+	keyUp     = "up"
+	keyDown   = "down"
+	keyEscape = "esc"
+	keyCtrlC  = "ctrl+c"
+)
+
+type command uint8
+
+const (
+	cmdNone command = iota
+	cmdAction
+	cmdPause
+	cmdQuit
+)
+
+// keyActions maps the keys to the game actions, with the same
+// default bindings as the graphical front end, replaced by its config ones.
+var keyActions = map[string]engine.Action{
+	keyLeft:  engine.MoveLeft,
+	keyRight: engine.MoveRight,
+	keyUp:    engine.DropHard,
+	keyDown:  engine.DropSoft,
+	"a":      engine.RotateLeft,
+	"z":      engine.RotateRight,
+	"c":      engine.Hold,
+}
+
+// keyPause is the key pausing the game, as set in the graphical front end.
+var keyPause = keyEscape
+
+// keymap returns the command for the key and its action, if any.
+// The escape and p keys always pause the game and the q and ctrl+c ones
+// quit it, unless bound to an action.
+func keymap(k string) (engine.Action, command) {
+	switch k {
+	case keyCtrlC:
+		return engine.None, cmdQuit
+	case keyPause:
+		return engine.None, cmdPause
+	}
+	if a, ok := keyActions[k]; ok {
+		return a, cmdAction
+	}
+	switch k {
+	case keyEscape, "p":
+		return engine.None, cmdPause
+	case "q":
+		return engine.None, cmdQuit
+	}
+	return engine.None, cmdNone
+}
+
+// escapes are the terminal sequences of the special keys.
+var escapes = map[string]string{
+	"\x1b[A": keyUp,
+	"\x1b[B": keyDown,
+	"\x1b[C": keyRight,
+	"\x1b[D": keyLeft,
+	"\x1bOA": keyUp,
+	"\x1bOB": keyDown,
+	"\x1bOC": keyRight,
+	"\x1bOD": keyLeft,
+}
+
+// readKeys decodes the keys read from r until it fails.
+// The terminal does not report key releases, its own key repeat applying.
+func readKeys(r io.Reader) <-chan string {
+	keys := make(chan string, 16)
+	go func() {
+		defer close(keys)
+		buf := make([]byte, 64)
+		for {
+			n, err := r.Read(buf)
+			for _, k := range decodeKeys(string(buf[:n])) {
+				keys <- k
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+	return keys
+}
+
+// decodeKeys splits the input into key names.
+// An escape character not starting a known sequence is the escape key.
+func decodeKeys(s string) (keys []string) {
+	for len(s) > 0 {
+		if s[0] == '\x1b' {
+			k := keyEscape
+			n := 1
+			for seq, name := range escapes {
+				if strings.HasPrefix(s, seq) {
+					k, n = name, len(seq)
+					break
+				}
+			}
+			keys = append(keys, k)
+			s = s[n:]
+			continue
+		}
+		switch c := s[0]; c {
+		case 3:
+			keys = append(keys, keyCtrlC)
+		default:
+			keys = append(keys, strings.ToLower(string(c)))
+		}
+		s = s[1:]
+	}
+	return
+}
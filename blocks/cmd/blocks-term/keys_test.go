@@ -0,0 +1,83 @@
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/pierrec/games/blocks/internal/colors"
+	"github.com/pierrec/games/blocks/internal/engine"
+)
+
+func TestDecodeKeys(t *testing.T) {
+	for _, tc := range []struct {
+		in   string
+		keys []string
+	}{
+		{"", nil},
+		{"aZ", []string{"a", "z"}},
+		{"\x1b[A\x1b[D", []string{keyUp, keyLeft}},
+		{"\x1bOB", []string{keyDown}},
+		{"\x1b", []string{keyEscape}},
+		{"\x1bq\x03", []string{keyEscape, "q", keyCtrlC}},
+	} {
+		if got, want := decodeKeys(tc.in), tc.keys; !reflect.DeepEqual(got, want) {
+			t.Errorf("%q: got %q; want %q", tc.in, got, want)
+		}
+	}
+}
+
+func TestKeymap(t *testing.T) {
+	for _, tc := range []struct {
+		key string
+		a   engine.Action
+		cmd command
+	}{
+		{keyUp, engine.DropHard, cmdAction},
+		{"c", engine.Hold, cmdAction},
+		{keyEscape, engine.None, cmdPause},
+		{"q", engine.None, cmdQuit},
+		{"x", engine.None, cmdNone},
+	} {
+		a, cmd := keymap(tc.key)
+		if a != tc.a || cmd != tc.cmd {
+			t.Errorf("%q: got %v, %v; want %v, %v", tc.key, a, cmd, tc.a, tc.cmd)
+		}
+	}
+}
+
+func TestConfigKeys(t *testing.T) {
+	actions, pause, col := keyActions, keyPause, blockColor
+	defer func() { keyActions, keyPause, blockColor = actions, pause, col }()
+
+	var cfg config
+	if err := json.Unmarshal([]byte(`{"blockcolor": 7, "Keys": [
+		{"key": "J"}, {"key": "L"}, {"key": "Space"}, {"key": "K"},
+		{"key": "Q"}, {"key": "↑"}, {"key": "P"}, {"key": "⇧"}
+	]}`), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	cfg.apply()
+	for _, tc := range []struct {
+		key string
+		a   engine.Action
+		cmd command
+	}{
+		{"j", engine.MoveLeft, cmdAction},
+		{" ", engine.DropHard, cmdAction},
+		{"q", engine.RotateLeft, cmdAction},
+		{keyUp, engine.RotateRight, cmdAction},
+		{"p", engine.None, cmdPause},
+		{keyEscape, engine.None, cmdPause},
+		{keyLeft, engine.None, cmdNone},
+		{keyCtrlC, engine.None, cmdQuit},
+	} {
+		a, cmd := keymap(tc.key)
+		if a != tc.a || cmd != tc.cmd {
+			t.Errorf("%q: got %v, %v; want %v, %v", tc.key, a, cmd, tc.a, tc.cmd)
+		}
+	}
+	if got, want := pieceColor(engine.I), colors.Green; got != want {
+		t.Errorf("got color %v; want %v", got, want)
+	}
+}
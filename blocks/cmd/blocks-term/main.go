@@ -0,0 +1,142 @@
+// Command blocks-term plays blocks in a terminal, for instance over SSH
+// where no window can be opened.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pierrec/games/blocks/internal/engine"
+)
+
+// frame is the delay between two screen updates.
+const frame = time.Second / 30
+
+func main() {
+	level := flag.Int("level", 0, "starting level, from 0 to 9")
+	mode := flag.String("mode", engine.ModeMarathon.String(), "game mode: "+modeNames())
+	previews := flag.Int("next", 1, fmt.Sprintf("number of next blocks, from 1 to %d", engine.MaxPreviews))
+	blocks := flag.String("blocks", engine.PiecesStandard.String(), "block set: "+setNames())
+	flag.Parse()
+
+	m, ok := engine.ParseMode(*mode)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown mode %q: %s\n", *mode, modeNames())
+		os.Exit(2)
+	}
+	set, ok := engine.ParsePieceSet(*blocks)
+	if !ok || set == engine.PiecesCustom {
+		fmt.Fprintf(os.Stderr, "unknown block set %q: %s\n", *blocks, setNames())
+		os.Exit(2)
+	}
+	if err := loadConfig(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if err := play(engine.Config{
+		Level:    max(0, min(*level, 9)),
+		Previews: *previews,
+		Mode:     m,
+		Pieces:   set,
+	}); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// play runs the game until it is over or the player quits.
+func play(cfg engine.Config) error {
+	restore, err := rawMode()
+	if err != nil {
+		return err
+	}
+	defer restore()
+
+	var scr screen
+	scr.Start(os.Stdout)
+	defer scr.Stop()
+
+	keys := readKeys(os.Stdin)
+	var g engine.Game
+	g.Start(cfg)
+	ticker := time.NewTicker(frame)
+	defer ticker.Stop()
+	clock := time.Now()
+	var paused bool
+	for {
+		select {
+		case k, ok := <-keys:
+			if !ok {
+				return nil
+			}
+			switch a, cmd := keymap(k); cmd {
+			case cmdQuit:
+				return nil
+			case cmdPause:
+				paused = !paused
+				// Do not account for the time spent paused.
+				clock = time.Now()
+			case cmdAction:
+				if !paused {
+					g.Step(a, 0)
+				}
+			}
+		case now := <-ticker.C:
+			if !paused {
+				g.Step(engine.None, now.Sub(clock))
+			}
+			clock = now
+		}
+		scr.Draw(&g, paused)
+		switch g.State() {
+		case engine.StateOver, engine.StateDone, engine.StateTimeUp:
+			// Wait for the player to leave.
+			for k := range keys {
+				if _, cmd := keymap(k); cmd == cmdQuit || cmd == cmdPause {
+					break
+				}
+			}
+			return nil
+		}
+	}
+}
+
+// modeNames returns the game modes as accepted on the command line.
+func modeNames() string {
+	var names []string
+	for m := engine.Mode(0); m < engine.Mode_; m++ {
+		names = append(names, modeName(m))
+	}
+	return strings.Join(names, ", ")
+}
+
+func modeName(m engine.Mode) string {
+	return strings.ToLower(strings.ReplaceAll(m.String(), " ", ""))
+}
+
+// setNames returns the block sets as accepted on the command line,
+// the custom blocks being only available in the graphical game.
+func setNames() string {
+	var names []string
+	for s := engine.PieceSet(0); s < engine.PiecesCustom; s++ {
+		names = append(names, strings.ToLower(s.String()))
+	}
+	return strings.Join(names, ", ")
+}
+
+func max(a, b int) int {
+	if a < b {
+		return b
+	}
+	return a
+}
+
+func min(a, b int) int {
+	if a > b {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/pierrec/games/blocks/internal/colors"
+	"github.com/pierrec/games/blocks/internal/engine"
+)
+
+// ANSI escape sequences.
+const (
+	ansiHome      = "\x1b[H"
+	ansiClear     = "\x1b[2J"
+	ansiClearLine = "\x1b[K"
+	ansiReset     = "\x1b[0m"
+	ansiHide      = "\x1b[?25l"
+	ansiShow      = "\x1b[?25h"
+)
+
+// Cell colors, blurred ones being blended with the black background
+// as the graphical front end does.
+var (
+	wallColor    = colors.Blue
+	garbageColor = blur(colors.White)
+	ghostColor   = blur(colors.White)
+)
+
+// blockColor is the color of the blocks, the default one of the graphical front end
+// unless set in its config.
+var blockColor = colors.Red
+
+// pieceColor returns the color of the piece cells.
+func pieceColor(id engine.PieceID) color.NRGBA {
+	return blockColor
+}
+
+// blur returns the color at half opacity over a black background.
+func blur(c color.NRGBA) color.NRGBA {
+	c.R, c.G, c.B = c.R/2, c.G/2, c.B/2
+	return c
+}
+
+// background returns the ANSI sequence setting the background color.
+func background(c color.NRGBA) string {
+	return fmt.Sprintf("\x1b[48;2;%d;%d;%dm", c.R, c.G, c.B)
+}
+
+// foreground returns the ANSI sequence setting the foreground color.
+func foreground(c color.NRGBA) string {
+	return fmt.Sprintf("\x1b[38;2;%d;%d;%dm", c.R, c.G, c.B)
+}
+
+// screen draws the game on the terminal, each cell being two characters wide.
+type screen struct {
+	w   io.Writer
+	buf strings.Builder
+}
+
+func (s *screen) Start(w io.Writer) {
+	s.w = w
+	io.WriteString(w, ansiClear+ansiHide)
+}
+
+func (s *screen) Stop() {
+	io.WriteString(s.w, ansiReset+ansiShow+"\r\n")
+}
+
+// Draw draws the board with the current piece and its ghost,
+// and the side panel with the next and hold pieces and the score.
+func (s *screen) Draw(g *engine.Game, paused bool) {
+	board := s.board(g)
+	panel := s.panel(g, paused)
+	buf := &s.buf
+	buf.Reset()
+	buf.WriteString(ansiHome)
+	for i := 0; i < len(board) || i < len(panel); i++ {
+		if i < len(board) {
+			buf.WriteString(board[i])
+		}
+		if i < len(panel) {
+			buf.WriteString("  ")
+			buf.WriteString(panel[i])
+		}
+		buf.WriteString(ansiClearLine + "\r\n")
+	}
+	io.WriteString(s.w, buf.String())
+}
+
+// board returns the lines of the board, without its hidden rows.
+func (s *screen) board(g *engine.Game) []string {
+	b := g.Board()
+	sz := b.Size()
+	cells := make([][]string, sz.Y)
+	for y := range cells {
+		cells[y] = make([]string, sz.X)
+		for x := range cells[y] {
+			switch c := b.Get(x, y); {
+			case c == engine.Empty:
+				cells[y][x] = "  "
+			case c == engine.Wall:
+				cells[y][x] = background(wallColor) + "  " + ansiReset
+			case c == engine.Garbage:
+				cells[y][x] = background(garbageColor) + "  " + ansiReset
+			default:
+				cells[y][x] = background(pieceColor(engine.PieceID(c-engine.Block))) + "  " + ansiReset
+			}
+		}
+	}
+	draw := func(p *engine.Piece, cell string) {
+		p.Walk(func(x, y, _, _ int) bool {
+			if y := p.Pos.Y + y; y >= 0 {
+				cells[y][p.Pos.X+x] = cell
+			}
+			return false
+		})
+	}
+	if p := g.Ghost(); p != nil {
+		draw(p, foreground(ghostColor)+"[]"+ansiReset)
+	}
+	if p := g.Current(); p != nil {
+		draw(p, background(pieceColor(p.ID))+"  "+ansiReset)
+	}
+	lines := make([]string, 0, sz.Y)
+	for _, row := range cells[b.Hidden():] {
+		lines = append(lines, strings.Join(row, ""))
+	}
+	return lines
+}
+
+// panel returns the lines of the side panel.
+func (s *screen) panel(g *engine.Game, paused bool) []string {
+	lines := []string{"NEXT"}
+	for _, p := range g.Queue() {
+		lines = append(lines, piece(&p)...)
+	}
+	lines = append(lines, "", "HOLD")
+	if p := g.Hold(); p != nil {
+		lines = append(lines, piece(p)...)
+	} else {
+		lines = append(lines, "", "")
+	}
+	sc := g.Score()
+	t := sc.Time
+	if left, ok := g.TimeLeft(); ok {
+		t = left
+	}
+	lines = append(lines, "",
+		fmt.Sprintf("SCORE %8d", sc.Total),
+		fmt.Sprintf("LINES %8d", sc.Lines),
+		fmt.Sprintf("LEVEL %8d", sc.Level),
+		fmt.Sprintf("TIME  %8s", formatTime(t)),
+		"",
+		g.Config().Mode.String(),
+	)
+	if sc.Last.Notable() {
+		lines = append(lines, sc.Last.String())
+	} else {
+		lines = append(lines, "")
+	}
+	switch st := g.State(); {
+	case paused:
+		lines = append(lines, "PAUSED - esc to resume, q to quit")
+	case st == engine.StateOver, st == engine.StateDone, st == engine.StateTimeUp:
+		lines = append(lines, st.String()+" - q to quit")
+	default:
+		lines = append(lines, "")
+	}
+	return lines
+}
+
+// piece returns the lines of the piece drawn in its spawn orientation,
+// at least 2 lines high to keep the panel layout steady.
+func piece(p *engine.Piece) []string {
+	left, top := p.Dims()
+	p.Walk(func(x, y, _, _ int) bool {
+		left, top = min(left, x), min(top, y)
+		return false
+	})
+	cells := make([][]bool, max(2, p.Height))
+	for y := range cells {
+		cells[y] = make([]bool, p.Width)
+	}
+	p.Walk(func(x, y, _, _ int) bool {
+		cells[y-top][x-left] = true
+		return false
+	})
+	cell := background(pieceColor(p.ID)) + "  " + ansiReset
+	lines := make([]string, len(cells))
+	for y, row := range cells {
+		for _, ok := range row {
+			if ok {
+				lines[y] += cell
+			} else {
+				lines[y] += "  "
+			}
+		}
+	}
+	return lines
+}
+
+// formatTime formats the duration as minutes, seconds and milliseconds.
+func formatTime(d time.Duration) string {
+	ms := int(d / time.Millisecond)
+	return fmt.Sprintf("%d:%02d.%03d", ms/60000, ms/1000%60, ms%1000)
+}
@@ -0,0 +1,7 @@
+package main
+
+import "errors"
+
+func rawMode() (restore func(), err error) {
+	return nil, errors.New("terminal not supported on windows")
+}
@@ -0,0 +1,250 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pierrec/games/blocks/internal/bot"
+	"github.com/pierrec/games/blocks/internal/engine"
+)
+
+// simUsage is the usage of the sim command.
+const simUsage = `usage: blocks sim [flags] [actions file]
+
+Run a game without window as fast as possible and print the final board
+followed by a JSON summary.
+
+The actions file, or the standard input if "-", lists a step per line:
+an action and an optional elapsed time, such as "moveleft" or "none 1.5s".
+Empty lines and lines starting with # are ignored.
+Without actions file, the game is played by the bot set with -bot.
+
+Flags:
+`
+
+// Bots available to the sim command.
+var simBots = map[string]func(seed int64) bot.Player{
+	"heuristic": func(int64) bot.Player { return new(bot.Bot) },
+	"random":    func(seed int64) bot.Player { return bot.NewRandom(seed) },
+}
+
+// simPlayer returns the next step of the game, ok being false once it has no more.
+type simPlayer func(g *engine.Game) (a engine.Action, dt time.Duration, ok bool)
+
+// simSummary is the outcome of a simulated game.
+type simSummary struct {
+	Seed   int64          `json:"seed"`
+	Mode   string         `json:"mode"`
+	State  string         `json:"state"`
+	Score  int            `json:"score"`
+	Lines  int            `json:"lines"`
+	Level  int            `json:"level"`
+	Time   int64          `json:"time_ms"`
+	Clears [4]int         `json:"clears"`
+	Pieces map[string]int `json:"pieces"`
+}
+
+// sim runs the sim command with the given arguments.
+func sim(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("sim", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprint(stderr, simUsage)
+		flags.PrintDefaults()
+	}
+	seed := flags.Int64("seed", 1, "seed of the game")
+	mode := flags.String("mode", engine.ModeMarathon.String(), "game mode")
+	level := flags.Int("level", 0, "starting level")
+	blocks := flags.String("blocks", engine.PiecesStandard.String(), "block set")
+	botName := flags.String("bot", "heuristic", "bot playing the game without actions file: "+simBotNames())
+	pieces := flags.Int("pieces", 1000, "maximum number of pieces played by the bot")
+	delay := flags.Duration("delay", 100*time.Millisecond, "elapsed time between two actions of the bot")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	cfg := engine.Config{
+		Seed:  *seed,
+		Level: *level,
+	}
+	var ok bool
+	if cfg.Mode, ok = engine.ParseMode(*mode); !ok {
+		return fmt.Errorf("unknown mode %q", *mode)
+	}
+	// The custom blocks are only available in the graphical game.
+	if cfg.Pieces, ok = engine.ParsePieceSet(*blocks); !ok || cfg.Pieces == engine.PiecesCustom {
+		return fmt.Errorf("unknown block set %q", *blocks)
+	}
+	var player simPlayer
+	switch name := flags.Arg(0); name {
+	case "":
+		newBot, ok := simBots[*botName]
+		if !ok {
+			return fmt.Errorf("unknown bot %q: %s", *botName, simBotNames())
+		}
+		player = limitPieces(botPlayer(newBot(*seed), *delay), *pieces)
+	case "-":
+		steps, err := readSteps(stdin)
+		if err != nil {
+			return err
+		}
+		player = scriptPlayer(steps)
+	default:
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		steps, err := readSteps(f)
+		_ = f.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		player = scriptPlayer(steps)
+	}
+
+	var g engine.Game
+	summary := simulate(&g, cfg, player)
+	if _, err := io.WriteString(stdout, g.Board().String()); err != nil {
+		return err
+	}
+	enc := json.NewEncoder(stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(summary)
+}
+
+// simulate plays the game with the player until it ends or the player stops.
+func simulate(g *engine.Game, cfg engine.Config, player simPlayer) simSummary {
+	pieces := make(map[string]int)
+	g.Paint = func(p *engine.Piece, x, y int) engine.Cell {
+		// Count the piece once, on its first cell.
+		if fx, fy := firstCell(p); x == fx && y == fy {
+			pieces[p.ID.String()]++
+		}
+		return engine.Block + engine.Cell(p.ID)
+	}
+	g.Start(cfg)
+	for g.State() == engine.StateRunning || g.State() == engine.StateClearing {
+		a, dt, ok := player(g)
+		if !ok {
+			break
+		}
+		g.Step(a, dt)
+	}
+	sc := g.Score()
+	return simSummary{
+		Seed:   g.Config().Seed,
+		Mode:   g.Config().Mode.String(),
+		State:  g.State().String(),
+		Score:  sc.Total,
+		Lines:  sc.Lines,
+		Level:  sc.Level,
+		Time:   sc.Time.Milliseconds(),
+		Clears: sc.Clears,
+		Pieces: pieces,
+	}
+}
+
+// firstCell returns the position of the first filled cell of the unrotated shape.
+func firstCell(p *engine.Piece) (x, y int) {
+	for y, row := range p.Data {
+		for x, ok := range row {
+			if ok {
+				return x, y
+			}
+		}
+	}
+	return -1, -1
+}
+
+// readSteps parses the steps of an actions file.
+func readSteps(r io.Reader) ([]engine.ReplayStep, error) {
+	var steps []engine.ReplayStep
+	s := bufio.NewScanner(r)
+	for n := 1; s.Scan(); n++ {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		step, err := parseStep(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		steps = append(steps, step)
+	}
+	return steps, s.Err()
+}
+
+// parseStep parses an action and its optional elapsed time.
+func parseStep(s string) (step engine.ReplayStep, err error) {
+	fields := strings.Fields(s)
+	if len(fields) > 2 {
+		return step, fmt.Errorf("invalid step %q", s)
+	}
+	a, ok := parseAction(fields[0])
+	if !ok {
+		return step, fmt.Errorf("unknown action %q", fields[0])
+	}
+	step.Action = a
+	if len(fields) == 2 {
+		step.Elapsed, err = time.ParseDuration(fields[1])
+		if err == nil && step.Elapsed < 0 {
+			err = errors.New("negative elapsed time")
+		}
+	}
+	return
+}
+
+func parseAction(s string) (engine.Action, bool) {
+	for a := engine.None; a <= engine.Hold; a++ {
+		if strings.EqualFold(s, a.String()) {
+			return a, true
+		}
+	}
+	return 0, false
+}
+
+func simBotNames() string {
+	var names []string
+	for name := range simBots {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// scriptPlayer plays the steps in order.
+func scriptPlayer(steps []engine.ReplayStep) simPlayer {
+	return func(*engine.Game) (engine.Action, time.Duration, bool) {
+		if len(steps) == 0 {
+			return engine.None, 0, false
+		}
+		s := steps[0]
+		steps = steps[1:]
+		return s.Action, s.Elapsed, true
+	}
+}
+
+// limitPieces stops the player once n pieces are locked.
+func limitPieces(player simPlayer, n int) simPlayer {
+	return func(g *engine.Game) (engine.Action, time.Duration, bool) {
+		if g.Score().Pieces >= n {
+			return engine.None, 0, false
+		}
+		return player(g)
+	}
+}
+
+// botPlayer plays the actions of the bot every delay.
+func botPlayer(p bot.Player, delay time.Duration) simPlayer {
+	return func(g *engine.Game) (engine.Action, time.Duration, bool) {
+		return p.Action(g), delay, true
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pierrec/games/blocks/internal/engine"
+)
+
+func TestReadSteps(t *testing.T) {
+	steps, err := readSteps(strings.NewReader("# start\nMoveLeft\n\n none 1.5s \nDROPHARD\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []engine.ReplayStep{
+		{Action: engine.MoveLeft},
+		{Action: engine.None, Elapsed: 1500 * time.Millisecond},
+		{Action: engine.DropHard},
+	}
+	if len(steps) != len(want) {
+		t.Fatalf("got %v; want %v", steps, want)
+	}
+	for i := range want {
+		if steps[i] != want[i] {
+			t.Fatalf("got %v; want %v", steps, want)
+		}
+	}
+
+	for _, s := range []string{"jump", "none 1s 2s", "none -1s", "none 1"} {
+		if _, err := readSteps(strings.NewReader(s)); err == nil {
+			t.Errorf("%q: expected error", s)
+		}
+	}
+}
+
+func TestSim(t *testing.T) {
+	run := func(args ...string) string {
+		out := new(bytes.Buffer)
+		if err := sim(args, strings.NewReader("drophard\ndrophard\n"), out, new(bytes.Buffer)); err != nil {
+			t.Fatal(err)
+		}
+		return out.String()
+	}
+	// Same seed, same game.
+	if a, b := run("-seed", "5", "-pieces", "100"), run("-seed", "5", "-pieces", "100"); a != b {
+		t.Fatalf("games differ:\n%s\n%s", a, b)
+	}
+
+	var g engine.Game
+	sum := simulate(&g, engine.Config{Seed: 1}, scriptPlayer([]engine.ReplayStep{
+		{Action: engine.DropHard},
+		{Action: engine.DropHard},
+		{Action: engine.Hold},
+		{Action: engine.DropHard},
+	}))
+	n := 0
+	for _, c := range sum.Pieces {
+		n += c
+	}
+	if got, want := n, 3; got != want {
+		t.Fatalf("got %d pieces; want %d", got, want)
+	}
+	if got, want := sum.State, engine.StateRunning.String(); got != want {
+		t.Fatalf("got %s; want %s", got, want)
+	}
+
+	if out := run("-mode", "sprint", "-"); !strings.Contains(out, `"mode": "Sprint"`) {
+		t.Fatalf("unexpected output:\n%s", out)
+	}
+	if err := sim([]string{"-mode", "none"}, nil, new(bytes.Buffer), new(bytes.Buffer)); err == nil {
+		t.Fatal("expected error")
+	}
+}
@@ -0,0 +1,142 @@
+// Package bot implements players driving a game on their own.
+package bot
+
+import (
+	"math"
+
+	"github.com/pierrec/games/blocks/internal/engine"
+)
+
+// Player decides the actions of a game.
+type Player interface {
+	// Action returns the next action to play on the game.
+	Action(g *engine.Game) engine.Action
+}
+
+// maxMoves is the number of actions played on a piece before dropping it
+// anyway, in case its target position cannot be reached.
+const maxMoves = 20
+
+// Bot is the Player locking every piece at the placement with the best evaluation,
+// looking ahead at the next piece and considering the hold one.
+type Bot struct {
+	Eval Evaluator // DefaultWeights.Eval if not set
+
+	pieces int // locked pieces when the target was set
+	id     engine.PieceID
+	target *engine.Piece // position to lock the current piece at, nil if not set
+	hold   bool          // whether or not to hold the current piece
+	moves  int           // actions played on the current piece
+}
+
+// Action returns the move bringing the current piece towards its best placement,
+// dropping it once reached.
+func (b *Bot) Action(g *engine.Game) engine.Action {
+	p := g.Current()
+	if p == nil {
+		return engine.None
+	}
+	if b.target == nil || b.pieces != g.Score().Pieces || b.id != p.ID {
+		b.plan(g)
+	}
+	b.moves++
+	switch {
+	case b.target == nil || b.moves > maxMoves:
+		return engine.DropHard
+	case b.hold:
+		b.target = nil
+		return engine.Hold
+	}
+	return Moves(p, b.target)[0]
+}
+
+// Moves returns the actions bringing the piece to the target placement
+// by rotating it in place, then moving it sideways and finally dropping it.
+func Moves(p, target *engine.Piece) []engine.Action {
+	var moves []engine.Action
+	switch r := p.Rot; target.Rot {
+	case r:
+	case r.Prev():
+		moves = append(moves, engine.RotateLeft)
+	default:
+		for ; r != target.Rot; r = r.Next() {
+			moves = append(moves, engine.RotateRight)
+		}
+	}
+	for x := p.Pos.X; x < target.Pos.X; x++ {
+		moves = append(moves, engine.MoveRight)
+	}
+	for x := p.Pos.X; x > target.Pos.X; x-- {
+		moves = append(moves, engine.MoveLeft)
+	}
+	return append(moves, engine.DropHard)
+}
+
+// Suggest returns the best placement for the current piece of the game, and
+// whether or not it is for the piece obtained by holding the current one.
+func (b *Bot) Suggest(g *engine.Game) (target *engine.Piece, hold bool) {
+	p := g.Current()
+	if p == nil {
+		return nil, false
+	}
+	board := g.Board()
+	queue := g.Queue()
+	next := queue[0]
+	best, target := b.best(board, *p, &next)
+	if !g.CanHold() {
+		return
+	}
+	// Holding swaps the current piece with the hold one, or the next one.
+	alt, after := g.Hold(), &next
+	if alt == nil {
+		alt, after = &next, nil
+		if len(queue) > 1 {
+			after = &queue[1]
+		}
+	}
+	q := engine.Piece{Shape: alt.Shape}
+	spawn(board, &q)
+	if v, t := b.best(board, q, after); t != nil && (target == nil || v > best) {
+		return t, true
+	}
+	return
+}
+
+// plan sets the target of the current piece.
+func (b *Bot) plan(g *engine.Game) {
+	p := g.Current()
+	b.pieces = g.Score().Pieces
+	b.id = p.ID
+	b.moves = 0
+	b.target, b.hold = b.Suggest(g)
+}
+
+// best returns the placement of the piece with the best evaluation,
+// taking the best placement of the next piece into account if not nil.
+func (b *Bot) best(board *engine.Board, p engine.Piece, next *engine.Piece) (float64, *engine.Piece) {
+	eval := b.Eval
+	if eval == nil {
+		eval = DefaultWeights.Eval
+	}
+	best := math.Inf(-1)
+	var target *engine.Piece
+	for _, q := range Placements(board, p) {
+		after, lines := Lock(board, &q)
+		v := eval(after, lines)
+		if next != nil {
+			n := engine.Piece{Shape: next.Shape}
+			spawn(after, &n)
+			v = math.Inf(-1)
+			for _, r := range Placements(after, n) {
+				end, more := Lock(after, &r)
+				v = math.Max(v, eval(end, lines+more))
+			}
+		}
+		if target == nil || v > best {
+			best = v
+			q := q
+			target = &q
+		}
+	}
+	return best, target
+}
@@ -0,0 +1,73 @@
+package bot
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/pierrec/games/blocks/internal/engine"
+)
+
+func TestPlacements(t *testing.T) {
+	var b engine.Board
+	b.Init(engine.Cols, engine.Rows, engine.HiddenRows)
+	for _, tc := range []struct {
+		id engine.PieceID
+		n  int
+	}{
+		{engine.O, 9},
+		{engine.I, 17},
+		{engine.T, 34},
+		{engine.S, 17},
+	} {
+		p := engine.Piece{Shape: engine.Tetrominoes[tc.id]}
+		spawn(&b, &p)
+		ps := Placements(&b, p)
+		if got, want := len(ps), tc.n; got != want {
+			t.Errorf("%v: got %d placements; want %d", tc.id, got, want)
+		}
+		for _, q := range ps {
+			if !q.Fits(&b) {
+				t.Fatalf("%v: placement %v does not fit", tc.id, q.Pos)
+			}
+			if q.Pos.Y++; q.Fits(&b) {
+				t.Fatalf("%v: placement %v not dropped", tc.id, q.Pos)
+			}
+		}
+	}
+}
+
+func TestBot(t *testing.T) {
+	var g engine.Game
+	g.Start(engine.Config{Seed: 1})
+	var b Bot
+	for g.Score().Pieces < 100 {
+		if g.State() == engine.StateOver {
+			t.Fatalf("game over after %d pieces", g.Score().Pieces)
+		}
+		g.Step(b.Action(&g), 50*time.Millisecond)
+	}
+	if got, want := g.Score().Lines, 30; got < want {
+		t.Fatalf("got %d lines; want at least %d", got, want)
+	}
+}
+
+func TestMoves(t *testing.T) {
+	p := engine.Piece{Shape: engine.Tetrominoes[engine.T]}
+	p.Pos.X = 4
+	for _, tc := range []struct {
+		rot   engine.Rotation
+		x     int
+		moves []engine.Action
+	}{
+		{engine.Rot0, 4, []engine.Action{engine.DropHard}},
+		{engine.Rot270, 2, []engine.Action{engine.RotateLeft, engine.MoveLeft, engine.MoveLeft, engine.DropHard}},
+		{engine.Rot180, 5, []engine.Action{engine.RotateRight, engine.RotateRight, engine.MoveRight, engine.DropHard}},
+	} {
+		target := p
+		target.Rot, target.Pos.X = tc.rot, tc.x
+		if got, want := Moves(&p, &target), tc.moves; fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("%v %d: got %v; want %v", tc.rot, tc.x, got, want)
+		}
+	}
+}
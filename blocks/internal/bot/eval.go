@@ -0,0 +1,71 @@
+package bot
+
+import "github.com/pierrec/games/blocks/internal/engine"
+
+// Evaluator rates the board resulting from locking pieces that cleared lines,
+// the best one having the highest value.
+type Evaluator func(b *engine.Board, lines int) float64
+
+// Weights are the factors of the board features used by its Eval method.
+type Weights struct {
+	Height    float64 // sum of the columns height
+	Lines     float64 // cleared lines
+	Holes     float64 // empty cells with a filled one above them
+	Bumpiness float64 // sum of the height differences of adjacent columns
+}
+
+// DefaultWeights favors clearing lines one at a time while keeping the board low.
+//
+// https://codemyroad.wordpress.com/2013/04/14/tetris-ai-the-near-perfect-player/
+var DefaultWeights = Weights{
+	Height:    -0.510066,
+	Lines:     0.760666,
+	Holes:     -0.35663,
+	Bumpiness: -0.184483,
+}
+
+// Eval is the Evaluator rating the board features with the weights.
+func (w Weights) Eval(b *engine.Board, lines int) float64 {
+	f := features(b)
+	return w.Height*float64(f.height) +
+		w.Lines*float64(lines) +
+		w.Holes*float64(f.holes) +
+		w.Bumpiness*float64(f.bumpiness)
+}
+
+type boardFeatures struct {
+	height, holes, bumpiness int
+}
+
+func features(b *engine.Board) (f boardFeatures) {
+	sz := b.Size()
+	rows := sz.Y - 1 // bottom wall
+	prev := -1
+	for x := 1; x < sz.X-1; x++ {
+		h := 0
+		for y := 0; y < rows; y++ {
+			if b.Get(x, y) == engine.Empty {
+				if h > 0 {
+					f.holes++
+				}
+				continue
+			}
+			if h == 0 {
+				h = rows - y
+			}
+		}
+		f.height += h
+		if prev >= 0 {
+			f.bumpiness += abs(h - prev)
+		}
+		prev = h
+	}
+	return
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
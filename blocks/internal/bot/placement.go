@@ -0,0 +1,81 @@
+package bot
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pierrec/games/blocks/internal/engine"
+)
+
+// Placements returns all the positions the piece can be locked at on the board
+// when rotated in place, then moved sideways and finally dropped,
+// each set of locked cells being returned once.
+func Placements(b *engine.Board, p engine.Piece) []engine.Piece {
+	var res []engine.Piece
+	seen := make(map[string]bool)
+	add := func(q engine.Piece) {
+		for q.Pos.Y++; q.Fits(b); q.Pos.Y++ {
+		}
+		q.Pos.Y--
+		if k := cellsKey(&q); !seen[k] {
+			seen[k] = true
+			res = append(res, q)
+		}
+	}
+	for r := engine.Rot0; r <= engine.Rot270; r++ {
+		q := p
+		q.Rot = r
+		if !q.Fits(b) {
+			continue
+		}
+		add(q)
+		for dx := -1; dx <= 1; dx += 2 {
+			for s := q; ; {
+				s.Pos.X += dx
+				if !s.Fits(b) {
+					break
+				}
+				add(s)
+			}
+		}
+	}
+	return res
+}
+
+// cellsKey identifies the board cells of the piece, whatever its rotation.
+func cellsKey(p *engine.Piece) string {
+	var cells []string
+	p.Walk(func(x, y, _, _ int) bool {
+		cells = append(cells, strconv.Itoa(p.Pos.X+x)+","+strconv.Itoa(p.Pos.Y+y))
+		return false
+	})
+	sort.Strings(cells)
+	return strings.Join(cells, " ")
+}
+
+// Lock returns a copy of the board with the piece locked onto it
+// and its full lines removed, as well as their number.
+func Lock(b *engine.Board, p *engine.Piece) (*engine.Board, int) {
+	b = b.Clone()
+	p.Walk(func(x, y, _, _ int) bool {
+		b.Set(p.Pos.X+x, p.Pos.Y+y, engine.Block+engine.Cell(p.ID))
+		return false
+	})
+	var lines int
+	_, h := p.Dims()
+	yn := b.Size().Y - 1 // bottom wall
+	for y := p.Pos.Y; y < p.Pos.Y+h && y < yn; y++ {
+		if y >= 0 && b.Full(y) {
+			b.RemoveLine(y)
+			lines++
+		}
+	}
+	return b, lines
+}
+
+// spawn positions the piece where the game places new pieces.
+func spawn(b *engine.Board, p *engine.Piece) {
+	p.Rot = engine.Rot0
+	p.Spawn(b)
+}
@@ -0,0 +1,29 @@
+package bot
+
+import (
+	"math/rand"
+
+	"github.com/pierrec/games/blocks/internal/engine"
+)
+
+// Random is the Player choosing its actions at random.
+type Random struct {
+	rnd *rand.Rand
+}
+
+// NewRandom returns a random player, which plays the same actions for the same seed.
+func NewRandom(seed int64) *Random {
+	return &Random{rnd: rand.New(rand.NewSource(seed))}
+}
+
+func (r *Random) Action(*engine.Game) engine.Action {
+	// Hard drops end the games too early when frequent.
+	if r.rnd.Intn(20) == 0 {
+		return engine.DropHard
+	}
+	actions := [...]engine.Action{
+		engine.None, engine.MoveLeft, engine.MoveRight, engine.DropSoft,
+		engine.RotateLeft, engine.RotateRight, engine.Hold,
+	}
+	return actions[r.rnd.Intn(len(actions))]
+}
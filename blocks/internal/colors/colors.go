@@ -0,0 +1,37 @@
+// Package colors defines the palette shared by the front ends,
+// without any user interface dependency.
+package colors
+
+import "image/color"
+
+var (
+	White = color.NRGBA{A: 255, R: 255, G: 255, B: 255}
+	Black = color.NRGBA{A: 255}
+	// Rainbow colors.
+	Red    = color.NRGBA{A: 255, R: 255}
+	Orange = color.NRGBA{A: 255, R: 255, G: 127}
+	Yellow = color.NRGBA{A: 255, R: 255, G: 255}
+	Green  = color.NRGBA{A: 255, G: 255}
+	Blue   = color.NRGBA{A: 255, B: 255}
+	Indigo = color.NRGBA{A: 255, R: 75, B: 130}
+	Violet = color.NRGBA{A: 255, R: 238, G: 130, B: 238}
+)
+
+// Block textures of the graphical front end, as saved in its config,
+// hold the index of their color in their lowest bits, from TextureFirst.
+const (
+	TextureMask  = 1<<10 - 1
+	TextureFirst = 2
+)
+
+// Palette lists the colors of the block textures in order.
+var Palette = [...]color.NRGBA{White, Black, Red, Orange, Yellow, Green, Blue, Indigo, Violet}
+
+// Texture returns the color of the block texture t, if it has one.
+func Texture(t uint16) (color.NRGBA, bool) {
+	i := int(t&TextureMask) - TextureFirst
+	if i < 0 || i >= len(Palette) {
+		return color.NRGBA{}, false
+	}
+	return Palette[i], true
+}
@@ -0,0 +1,193 @@
+package engine
+
+import (
+	"image"
+	"strings"
+)
+
+// Default playfield dimensions, excluding the walls and hidden rows.
+const (
+	Cols = 10
+	Rows = 20
+)
+
+// HiddenRows is the default number of rows above the visible playfield
+// allowing blocks to rotate while on top.
+const HiddenRows = 1
+
+// Playfield dimension ranges.
+const (
+	MinCols   = 4
+	MaxCols   = 20
+	MinRows   = 8
+	MaxRows   = 40
+	MinHidden = 1
+	MaxHidden = 4
+)
+
+// Cell defines the content of a board cell.
+//
+// Values from Block upwards are set by the Game Paint function
+// and are only relevant to the front end, the engine only
+// distinguishing empty, garbage and other non empty cells.
+type Cell uint16
+
+const (
+	Empty   Cell = iota // no content
+	Wall                // playfield border
+	Garbage             // garbage line cell
+	Block               // first value for block cells
+)
+
+func (c Cell) String() string {
+	switch c {
+	case Empty:
+		return "."
+	case Wall:
+		return "#"
+	case Garbage:
+		return "X"
+	}
+	if id := PieceID(c - Block); id < PieceID(len(Tetrominoes)) {
+		return id.String()
+	}
+	return "*"
+}
+
+// Board is the playfield surrounded by walls on its left, right and bottom sides.
+// Its top rows are hidden.
+type Board struct {
+	data   [][]Cell
+	hidden int
+}
+
+// Init initializes an empty board with a playfield of cols by rows cells
+// and hidden rows above it.
+func (b *Board) Init(cols, rows, hidden int) {
+	b.hidden = hidden
+	cols, rows = cols+2, rows+hidden+1
+	cells := make([]Cell, cols*rows)
+	b.data = make([][]Cell, rows)
+	for i := range b.data {
+		b.data[i] = cells[:cols:cols]
+		cells = cells[cols:]
+	}
+	b.Clear()
+}
+
+// Clear empties the board and draws its walls.
+func (b *Board) Clear() {
+	sz := b.Size()
+	cols, rows := sz.X, sz.Y
+	for y := 0; y < rows; y++ {
+		for x := 0; x < cols; x++ {
+			c := Empty
+			if x == 0 || x == cols-1 || y == rows-1 {
+				c = Wall
+			}
+			b.data[y][x] = c
+		}
+	}
+}
+
+// Size returns the width and height of the board as a point,
+// including its walls and hidden rows.
+func (b *Board) Size() image.Point {
+	if len(b.data) == 0 {
+		return image.Point{}
+	}
+	return image.Pt(len(b.data[0]), len(b.data))
+}
+
+// Clone returns a copy of the board.
+func (b *Board) Clone() *Board {
+	sz := b.Size()
+	cells := make([]Cell, sz.X*sz.Y)
+	c := &Board{data: make([][]Cell, sz.Y), hidden: b.hidden}
+	for i, row := range b.data {
+		c.data[i] = cells[:sz.X:sz.X]
+		copy(c.data[i], row)
+		cells = cells[sz.X:]
+	}
+	return c
+}
+
+// Hidden returns the number of rows above the visible playfield.
+func (b *Board) Hidden() int {
+	return b.hidden
+}
+
+// Empty reports whether or not the board has no blocks.
+func (b *Board) Empty() bool {
+	for _, row := range b.data {
+		for _, c := range row {
+			if c >= Garbage {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (b *Board) Get(x, y int) Cell {
+	return b.data[y][x]
+}
+
+func (b *Board) Set(x, y int, c Cell) {
+	b.data[y][x] = c
+}
+
+// Full reports whether or not the playfield line at y has no empty cell.
+func (b *Board) Full(y int) bool {
+	for _, c := range b.data[y] {
+		if c == Empty {
+			return false
+		}
+	}
+	return true
+}
+
+// RemoveLine removes the playfield line at y and moves all the ones above it down.
+func (b *Board) RemoveLine(y int) {
+	xn := b.Size().X - 1 // left wall correction applied
+	for ; y >= 0; y-- {
+		for x := 1; x < xn; x++ {
+			c := Empty
+			if y > 0 {
+				c = b.data[y-1][x]
+			}
+			b.data[y][x] = c
+		}
+	}
+}
+
+// PushLine moves all the playfield lines up and sets the bottom one with the
+// cells returned by fn for its columns, from 1 to the playfield width.
+// It reports whether or not blocks were pushed out of the top of the board.
+func (b *Board) PushLine(fn func(x int) Cell) (out bool) {
+	sz := b.Size()
+	xn, yn := sz.X-1, sz.Y-1 // wall corrections applied
+	for x := 1; x < xn; x++ {
+		out = out || b.data[0][x] != Empty
+	}
+	for y := 0; y < yn-1; y++ {
+		copy(b.data[y][1:xn], b.data[y+1][1:xn])
+	}
+	for x := 1; x < xn; x++ {
+		b.data[yn-1][x] = fn(x)
+	}
+	return
+}
+
+func (b *Board) String() string {
+	buf := new(strings.Builder)
+
+	for _, row := range b.data {
+		for _, c := range row {
+			buf.WriteString(c.String())
+		}
+		buf.WriteString("\n")
+	}
+
+	return buf.String()
+}
@@ -0,0 +1,237 @@
+// Code generated by "stringer -type PieceID,State,Action,Random,RotationSystem,LockReset,Scoring,Mode,PieceSet -linecomment -output engine_string.go"; DO NOT EDIT.
+
+package engine
+
+import "strconv"
+
+func _() {
+	// An "invalid array index" compiler error signifies that the constant values have changed.
+	// Re-run the stringer command to generate them again.
+	var x [1]struct{}
+	_ = x[I-0]
+	_ = x[J-1]
+	_ = x[L-2]
+	_ = x[O-3]
+	_ = x[S-4]
+	_ = x[T-5]
+	_ = x[Z-6]
+	_ = x[F5-7]
+	_ = x[F5R-8]
+	_ = x[I5-9]
+	_ = x[L5-10]
+	_ = x[L5R-11]
+	_ = x[N5-12]
+	_ = x[N5R-13]
+	_ = x[P5-14]
+	_ = x[P5R-15]
+	_ = x[T5-16]
+	_ = x[U5-17]
+	_ = x[V5-18]
+	_ = x[W5-19]
+	_ = x[X5-20]
+	_ = x[Y5-21]
+	_ = x[Y5R-22]
+	_ = x[Z5-23]
+	_ = x[Z5R-24]
+	_ = x[I2-25]
+	_ = x[I3-26]
+	_ = x[L3-27]
+	_ = x[BigI-28]
+	_ = x[BigJ-29]
+	_ = x[BigL-30]
+	_ = x[BigO-31]
+	_ = x[BigS-32]
+	_ = x[BigT-33]
+	_ = x[BigZ-34]
+	_ = x[Custom-35]
+}
+
+const _PieceID_name = "IJLOSTZF5F5'I5L5L5'N5N5'P5P5'T5U5V5W5X5Y5Y5'Z5Z5'I2I3L3BIG IBIG JBIG LBIG OBIG SBIG TBIG ZCUSTOM"
+
+var _PieceID_index = [...]uint8{0, 1, 2, 3, 4, 5, 6, 7, 9, 12, 14, 16, 19, 21, 24, 26, 29, 31, 33, 35, 37, 39, 41, 44, 46, 49, 51, 53, 55, 60, 65, 70, 75, 80, 85, 90, 96}
+
+func (i PieceID) String() string {
+	idx := int(i) - 0
+	if i < 0 || idx >= len(_PieceID_index)-1 {
+		return "PieceID(" + strconv.FormatInt(int64(i), 10) + ")"
+	}
+	return _PieceID_name[_PieceID_index[idx]:_PieceID_index[idx+1]]
+}
+func _() {
+	// An "invalid array index" compiler error signifies that the constant values have changed.
+	// Re-run the stringer command to generate them again.
+	var x [1]struct{}
+	_ = x[StateNone-0]
+	_ = x[StateRunning-1]
+	_ = x[StateClearing-2]
+	_ = x[StateOver-3]
+	_ = x[StateDone-4]
+	_ = x[StateTimeUp-5]
+}
+
+const _State_name = "NOSTATERUNNINGCLEARINGGAME OVERCOMPLETETIME UP"
+
+var _State_index = [...]uint8{0, 7, 14, 22, 31, 39, 46}
+
+func (i State) String() string {
+	idx := int(i) - 0
+	if i < 0 || idx >= len(_State_index)-1 {
+		return "State(" + strconv.FormatInt(int64(i), 10) + ")"
+	}
+	return _State_name[_State_index[idx]:_State_index[idx+1]]
+}
+func _() {
+	// An "invalid array index" compiler error signifies that the constant values have changed.
+	// Re-run the stringer command to generate them again.
+	var x [1]struct{}
+	_ = x[None-0]
+	_ = x[MoveLeft-1]
+	_ = x[MoveRight-2]
+	_ = x[DropSoft-3]
+	_ = x[DropHard-4]
+	_ = x[RotateLeft-5]
+	_ = x[RotateRight-6]
+	_ = x[Hold-7]
+}
+
+const _Action_name = "NONEMOVELEFTMOVERIGHTDROPSOFTDROPHARDROTATELEFTROTATERIGHTHOLD"
+
+var _Action_index = [...]uint8{0, 4, 12, 21, 29, 37, 47, 58, 62}
+
+func (i Action) String() string {
+	idx := int(i) - 0
+	if i < 0 || idx >= len(_Action_index)-1 {
+		return "Action(" + strconv.FormatInt(int64(i), 10) + ")"
+	}
+	return _Action_name[_Action_index[idx]:_Action_index[idx+1]]
+}
+func _() {
+	// An "invalid array index" compiler error signifies that the constant values have changed.
+	// Re-run the stringer command to generate them again.
+	var x [1]struct{}
+	_ = x[RandomPure-0]
+	_ = x[RandomBag7-1]
+	_ = x[RandomBag14-2]
+	_ = x[RandomNES-3]
+	_ = x[RandomTGM-4]
+	_ = x[Random_-5]
+}
+
+const _Random_name = "Random7-bag14-bagNESTGMRandom_"
+
+var _Random_index = [...]uint8{0, 6, 11, 17, 20, 23, 30}
+
+func (i Random) String() string {
+	idx := int(i) - 0
+	if i < 0 || idx >= len(_Random_index)-1 {
+		return "Random(" + strconv.FormatInt(int64(i), 10) + ")"
+	}
+	return _Random_name[_Random_index[idx]:_Random_index[idx+1]]
+}
+func _() {
+	// An "invalid array index" compiler error signifies that the constant values have changed.
+	// Re-run the stringer command to generate them again.
+	var x [1]struct{}
+	_ = x[RotationClassic-0]
+	_ = x[RotationSRS-1]
+	_ = x[RotationSystem_-2]
+}
+
+const _RotationSystem_name = "ClassicSRSRotationSystem_"
+
+var _RotationSystem_index = [...]uint8{0, 7, 10, 25}
+
+func (i RotationSystem) String() string {
+	idx := int(i) - 0
+	if i < 0 || idx >= len(_RotationSystem_index)-1 {
+		return "RotationSystem(" + strconv.FormatInt(int64(i), 10) + ")"
+	}
+	return _RotationSystem_name[_RotationSystem_index[idx]:_RotationSystem_index[idx+1]]
+}
+func _() {
+	// An "invalid array index" compiler error signifies that the constant values have changed.
+	// Re-run the stringer command to generate them again.
+	var x [1]struct{}
+	_ = x[LockResetMove-0]
+	_ = x[LockResetInfinite-1]
+	_ = x[LockResetStep-2]
+	_ = x[LockReset_-3]
+}
+
+const _LockReset_name = "15 movesInfiniteStepLockReset_"
+
+var _LockReset_index = [...]uint8{0, 8, 16, 20, 30}
+
+func (i LockReset) String() string {
+	idx := int(i) - 0
+	if i < 0 || idx >= len(_LockReset_index)-1 {
+		return "LockReset(" + strconv.FormatInt(int64(i), 10) + ")"
+	}
+	return _LockReset_name[_LockReset_index[idx]:_LockReset_index[idx+1]]
+}
+func _() {
+	// An "invalid array index" compiler error signifies that the constant values have changed.
+	// Re-run the stringer command to generate them again.
+	var x [1]struct{}
+	_ = x[ScoringNES-0]
+	_ = x[ScoringGuideline-1]
+	_ = x[Scoring_-2]
+}
+
+const _Scoring_name = "NESGuidelineScoring_"
+
+var _Scoring_index = [...]uint8{0, 3, 12, 20}
+
+func (i Scoring) String() string {
+	idx := int(i) - 0
+	if i < 0 || idx >= len(_Scoring_index)-1 {
+		return "Scoring(" + strconv.FormatInt(int64(i), 10) + ")"
+	}
+	return _Scoring_name[_Scoring_index[idx]:_Scoring_index[idx+1]]
+}
+func _() {
+	// An "invalid array index" compiler error signifies that the constant values have changed.
+	// Re-run the stringer command to generate them again.
+	var x [1]struct{}
+	_ = x[ModeMarathon-0]
+	_ = x[ModeSprint-1]
+	_ = x[ModeUltra-2]
+	_ = x[ModeCheese-3]
+	_ = x[ModeSurvival-4]
+	_ = x[Mode_-5]
+}
+
+const _Mode_name = "MarathonSprintUltraCheese RaceSurvivalMode_"
+
+var _Mode_index = [...]uint8{0, 8, 14, 19, 30, 38, 43}
+
+func (i Mode) String() string {
+	idx := int(i) - 0
+	if i < 0 || idx >= len(_Mode_index)-1 {
+		return "Mode(" + strconv.FormatInt(int64(i), 10) + ")"
+	}
+	return _Mode_name[_Mode_index[idx]:_Mode_index[idx+1]]
+}
+func _() {
+	// An "invalid array index" compiler error signifies that the constant values have changed.
+	// Re-run the stringer command to generate them again.
+	var x [1]struct{}
+	_ = x[PiecesStandard-0]
+	_ = x[PiecesPentominoes-1]
+	_ = x[PiecesTiny-2]
+	_ = x[PiecesBig-3]
+	_ = x[PiecesCustom-4]
+	_ = x[PieceSet_-5]
+}
+
+const _PieceSet_name = "StandardPentominoesTinyBigCustomPieceSet_"
+
+var _PieceSet_index = [...]uint8{0, 8, 19, 23, 26, 32, 41}
+
+func (i PieceSet) String() string {
+	idx := int(i) - 0
+	if i < 0 || idx >= len(_PieceSet_index)-1 {
+		return "PieceSet(" + strconv.FormatInt(int64(i), 10) + ")"
+	}
+	return _PieceSet_name[_PieceSet_index[idx]:_PieceSet_index[idx+1]]
+}
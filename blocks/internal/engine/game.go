@@ -0,0 +1,493 @@
+// Package engine implements the game rules without any user interface,
+// so that the same game can be driven by any front end, bot or test.
+package engine
+
+import (
+	"image"
+	"math/rand"
+	"time"
+)
+
+//go:generate go run golang.org/x/tools/cmd/stringer -type PieceID,State,Action,Random,RotationSystem,LockReset,Scoring,Mode,PieceSet -linecomment -output engine_string.go
+
+type State uint8
+
+const (
+	StateNone     State = iota // NOSTATE
+	StateRunning               // RUNNING
+	StateClearing              // CLEARING
+	StateOver                  // GAME OVER
+	StateDone                  // COMPLETE
+	StateTimeUp                // TIME UP
+)
+
+// Action is a player input.
+type Action uint8
+
+const (
+	None        Action = iota // NONE
+	MoveLeft                  // MOVELEFT
+	MoveRight                 // MOVERIGHT
+	DropSoft                  // DROPSOFT
+	DropHard                  // DROPHARD
+	RotateLeft                // ROTATELEFT
+	RotateRight               // ROTATERIGHT
+	Hold                      // HOLD
+)
+
+// Config defines the parameters of a game.
+type Config struct {
+	Level      int
+	Seed       int64 // seed for the randomizer, set from the current time if zero
+	Randomizer Random
+	Rotation   RotationSystem
+	LockDelay  time.Duration // delay before a piece on the ground locks, zero locking it on the next move down
+	LockReset  LockReset
+	Previews   int  // number of next pieces, from 1 to MaxPreviews
+	AllSpin    bool // detect the spins of all pieces, not only the T one
+	Scoring    Scoring
+	Mode       Mode
+	TimeLimit  time.Duration // game duration in the Ultra mode, the first UltraTimes if not set
+	Garbage    int           // garbage lines at start in the Cheese Race mode, CheeseLines if not set
+	Messiness  float64       // probability from 0 to 1 for the hole of a garbage line to change column
+	Cols       int           // playfield width, Cols if not set
+	Rows       int           // playfield height, Rows if not set
+	Hidden     int           // rows above the playfield, HiddenRows if not set
+	Pieces     PieceSet      // piece set, PiecesStandard if not set
+	Shapes     []Shape       // pieces of the PiecesCustom set, the standard set being used if none or not fitting on the board
+}
+
+// MaxPreviews is the maximum number of next pieces.
+const MaxPreviews = 6
+
+// Game manages the board, the current piece, the queue of next pieces and the score.
+//
+// A game is driven by calling Step with the player actions and
+// the elapsed time, gravity moving the current piece down.
+// When full lines are detected, the game is in the StateClearing state
+// until ClearLines is called, which is done by the next Step if need be.
+type Game struct {
+	// Paint returns the board cell for the piece cell at (x, y) of its unrotated shape.
+	// If not set, the cell is set to Block plus the piece ID.
+	Paint func(p *Piece, x, y int) Cell
+
+	config  Config
+	shapes  []Shape // pieces of the set
+	random  Randomizer
+	garbage *rand.Rand    // garbage holes randomizer
+	hole    int           // column of the last garbage hole, 0 if none
+	tide    time.Duration // time left before the next garbage line in the Survival mode
+	state   State
+	board   Board
+	current Piece
+	next    []Piece
+	ghost   Piece
+	hold    Piece
+	held    bool // whether or not there is a hold piece
+	holdOK  bool // whether or not the current piece can be held
+	score   Score
+	lines   []int
+	kick    int           // kick index of the last rotation, -1 if the piece moved since
+	drops   int           // lines soft dropped by the player for the current piece
+	hard    int           // lines hard dropped by the player for the current piece
+	fall    time.Duration // elapsed time since the last move down
+	// Lock delay.
+	locking  bool          // whether or not the current piece is on the ground
+	lockLeft time.Duration // remaining lock delay
+	lowest   int           // lowest line reached by the current piece
+	resets   int           // number of lock delay restarts on the lowest line
+	// Replay.
+	replay Replay
+	merge  bool // whether or not the last step can be extended
+}
+
+// Start starts a new game.
+func (g *Game) Start(cfg Config) {
+	if cfg.Seed == 0 {
+		cfg.Seed = time.Now().UnixNano()
+	}
+	cfg.Previews = max(1, min(cfg.Previews, MaxPreviews))
+	if cfg.TimeLimit <= 0 {
+		cfg.TimeLimit = UltraTimes[0]
+	}
+	if cfg.Garbage <= 0 {
+		cfg.Garbage = CheeseLines
+	}
+	cfg.Cols = dimension(cfg.Cols, Cols, MinCols, MaxCols)
+	cfg.Rows = dimension(cfg.Rows, Rows, MinRows, MaxRows)
+	cfg.Hidden = dimension(cfg.Hidden, HiddenRows, MinHidden, MaxHidden)
+	if cfg.Pieces != PiecesCustom {
+		// Make room for the largest pieces of the set.
+		n := cfg.Pieces.Size()
+		cfg.Cols, cfg.Rows = max(cfg.Cols, n), max(cfg.Rows, n)
+	}
+	// Leave room for the pieces to spawn.
+	cfg.Garbage = min(cfg.Garbage, cfg.Rows-4)
+	if cfg.Pieces != PiecesCustom {
+		cfg.Shapes = nil
+	} else if !fitShapes(cfg.Shapes, cfg) {
+		cfg.Pieces, cfg.Shapes = PiecesStandard, nil
+	}
+	g.config = cfg
+	g.shapes = cfg.Shapes
+	if cfg.Pieces != PiecesCustom {
+		g.shapes = cfg.Pieces.Shapes()
+	}
+	g.replay = Replay{Config: cfg}
+	g.merge = false
+	g.random = cfg.Randomizer.New(cfg.Seed, len(g.shapes))
+	g.garbage = rand.New(rand.NewSource(cfg.Seed))
+	g.hole = 0
+	g.state = StateRunning
+	g.board.Init(cfg.Cols, cfg.Rows, cfg.Hidden)
+	if cfg.Mode == ModeCheese {
+		g.pushGarbage(cfg.Garbage)
+	}
+	g.score = Score{Level: cfg.Level, Rule: cfg.Scoring}
+	g.lines = g.lines[:0]
+	g.drops, g.hard = 0, 0
+	g.fall = 0
+	g.tide = SurvivalStart
+	g.held = false
+	g.next = g.next[:0]
+	for i := 0; i < cfg.Previews; i++ {
+		g.next = append(g.next, g.newPiece())
+	}
+	g.spawn()
+}
+
+// fitShapes reports whether or not there are shapes, all passing Shape.Check
+// on the board of the config.
+func fitShapes(shapes []Shape, cfg Config) bool {
+	for _, s := range shapes {
+		if s.Check(cfg.Cols, cfg.Rows, cfg.Hidden) != nil {
+			return false
+		}
+	}
+	return len(shapes) > 0
+}
+
+// dimension returns the board dimension v within [lo, hi], or def if not set.
+func dimension(v, def, lo, hi int) int {
+	if v == 0 {
+		return def
+	}
+	return max(lo, min(v, hi))
+}
+
+// Config returns the game configuration, with its seed set and other values adjusted.
+func (g *Game) Config() Config {
+	return g.config
+}
+
+func (g *Game) State() State {
+	return g.state
+}
+
+func (g *Game) Board() *Board {
+	return &g.board
+}
+
+// Current returns the piece being played, if any.
+func (g *Game) Current() *Piece {
+	if g.state != StateRunning {
+		return nil
+	}
+	return &g.current
+}
+
+// Ghost returns the current piece at the lowest position it can be dropped to, if any.
+func (g *Game) Ghost() *Piece {
+	p := g.Current()
+	if p == nil {
+		return nil
+	}
+	g.ghost = *p
+	for p = &g.ghost; p.Fits(&g.board); {
+		p.Pos.Y++
+	}
+	p.Pos.Y--
+	return p
+}
+
+// Next returns the next piece.
+func (g *Game) Next() *Piece {
+	return &g.next[0]
+}
+
+// Queue returns the next pieces in order.
+func (g *Game) Queue() []Piece {
+	return g.next
+}
+
+// Hold returns the hold piece, if any.
+func (g *Game) Hold() *Piece {
+	if !g.held {
+		return nil
+	}
+	return &g.hold
+}
+
+// CanHold reports whether or not the current piece can be swapped with the hold one,
+// which can only be done once per piece.
+func (g *Game) CanHold() bool {
+	return g.holdOK
+}
+
+func (g *Game) Score() Score {
+	return g.score
+}
+
+// Lines returns the position of the full lines to be cleared in ascending order.
+func (g *Game) Lines() []int {
+	if g.state != StateClearing {
+		return nil
+	}
+	return g.lines
+}
+
+// Kick returns the index of the kick used by the last rotation of the current piece,
+// or -1 if it was not rotated or moved since.
+func (g *Game) Kick() int {
+	return g.kick
+}
+
+// Gravity returns the current duration between two moves down.
+func (g *Game) Gravity() time.Duration {
+	return Gravity(g.score.Level)
+}
+
+// Locking returns the remaining lock delay of the current piece
+// and whether or not it is on the ground.
+func (g *Game) Locking() (left time.Duration, ok bool) {
+	if g.state != StateRunning || !g.locking {
+		return 0, false
+	}
+	return g.lockLeft, true
+}
+
+// Step applies the action to the current piece, then moves it down
+// once for every gravity period elapsed in dt, or locks it once its
+// lock delay has elapsed if it is on the ground.
+func (g *Game) Step(a Action, dt time.Duration) {
+	g.record(a, dt)
+	defer func() { g.merge = g.state == StateRunning }()
+	if g.state == StateClearing {
+		g.ClearLines()
+	}
+	if g.state != StateRunning {
+		return
+	}
+	g.do(a)
+	if left, ok := g.TimeLeft(); ok && dt > left {
+		dt = left
+	}
+	g.rise(dt)
+	if g.state == StateRunning {
+		g.state = g.goal()
+	}
+}
+
+// elapse moves the current piece down or locks it for the elapsed time dt.
+// It returns the time left once the game stopped running, if any.
+func (g *Game) elapse(dt time.Duration) time.Duration {
+	for g.state == StateRunning {
+		if g.locking {
+			if dt < g.lockLeft {
+				g.lockLeft -= dt
+				return 0
+			}
+			dt -= g.lockLeft
+			g.lock()
+			continue
+		}
+		d := g.Gravity() - g.fall
+		if dt < d {
+			g.fall += dt
+			return 0
+		}
+		dt -= d
+		g.fall = 0
+		g.moveDown()
+	}
+	return dt
+}
+
+// ClearLines removes the full lines, updates the score and
+// starts the next piece. It returns whether or not the level changed.
+func (g *Game) ClearLines() (newLevel bool) {
+	if g.state != StateClearing {
+		return false
+	}
+	for _, y := range g.lines {
+		g.board.RemoveLine(y)
+	}
+	g.score.Last.Perfect = g.board.Empty()
+	newLevel = g.score.NewLines(g.score.Last)
+	g.lines = g.lines[:0]
+	if s := g.goal(); s != StateRunning {
+		g.state = s
+		return
+	}
+	g.state = StateRunning
+	g.spawn()
+	return
+}
+
+func (g *Game) do(a Action) {
+	switch a {
+	case MoveLeft:
+		g.move(-1, 0)
+	case MoveRight:
+		g.move(1, 0)
+	case DropSoft:
+		if !g.moveDown() {
+			return
+		}
+		g.drops++
+	case DropHard:
+		for g.move(0, 1) {
+			g.hard++
+		}
+		g.lock()
+	case RotateLeft:
+		g.rotate(g.current.Rot.Prev())
+	case RotateRight:
+		g.rotate(g.current.Rot.Next())
+	case Hold:
+		if !g.holdOK {
+			return
+		}
+		hold := Piece{Shape: g.current.Shape}
+		if g.held {
+			g.current = g.hold
+			g.place()
+		} else {
+			g.spawn()
+		}
+		g.hold, g.held = hold, true
+		g.holdOK = false
+		g.drops, g.hard = 0, 0
+	}
+}
+
+// newPiece returns the next piece from the randomizer.
+func (g *Game) newPiece() Piece {
+	id := g.random.Next()
+	return Piece{Shape: g.shapes[id]}
+}
+
+// spawn uses the next piece as the current one.
+func (g *Game) spawn() {
+	n := len(g.next)
+	g.current = g.next[0]
+	copy(g.next, g.next[1:])
+	g.next[n-1] = g.newPiece()
+	g.holdOK = true
+	g.place()
+}
+
+// place positions the current piece at the top middle of the board.
+// The game is over if it does not fit.
+func (g *Game) place() {
+	g.fall = 0
+	g.kick = -1
+	p := &g.current
+	p.Spawn(&g.board)
+	if !p.Fits(&g.board) {
+		g.state = StateOver
+		return
+	}
+	g.locking = false
+	g.lockLeft = g.config.LockDelay
+	g.lowest = p.Pos.Y
+	g.resets = 0
+	g.ground()
+}
+
+func (g *Game) move(dx, dy int) bool {
+	p := &g.current
+	p.Pos.X += dx
+	p.Pos.Y += dy
+	if p.Fits(&g.board) {
+		g.kick = -1
+		g.resetLock()
+		g.ground()
+		return true
+	}
+	p.Pos.X -= dx
+	p.Pos.Y -= dy
+	return false
+}
+
+// rotate rotates the current piece, trying all the kicks of the rotation system in order.
+func (g *Game) rotate(r Rotation) bool {
+	p := &g.current
+	rot, pos := p.Rot, p.Pos
+	for i, k := range g.config.Rotation.kicks(p, r) {
+		p.Rot = r
+		p.Pos = pos.Add(k)
+		if p.Fits(&g.board) {
+			g.kick = i
+			g.resetLock()
+			g.ground()
+			return true
+		}
+	}
+	p.Rot, p.Pos = rot, pos
+	return false
+}
+
+// fits reports whether or not the current piece fits once moved by (dx, dy).
+func (g *Game) fits(dx, dy int) bool {
+	p := g.current
+	p.Pos = p.Pos.Add(image.Pt(dx, dy))
+	return p.Fits(&g.board)
+}
+
+// moveDown moves the current piece one line down and locks it if it cannot
+// and there is no lock delay.
+func (g *Game) moveDown() bool {
+	if g.move(0, 1) {
+		return true
+	}
+	if g.config.LockDelay == 0 {
+		g.lock()
+	}
+	return false
+}
+
+// lock writes the current piece onto the board and checks for full lines.
+func (g *Game) lock() {
+	p := &g.current
+	spin := g.spin()
+	paint := g.Paint
+	if paint == nil {
+		paint = func(p *Piece, _, _ int) Cell { return Block + Cell(p.ID) }
+	}
+	p.Walk(func(x, y, sx, sy int) bool {
+		g.board.Set(p.Pos.X+x, p.Pos.Y+y, paint(p, sx, sy))
+		return false
+	})
+	g.score.NewBlock(g.drops, g.hard)
+	g.drops, g.hard = 0, 0
+	g.locking = false
+
+	// Detect full lines.
+	lines := g.lines[:0]
+	_, h := p.Dims()
+	yn := min(p.Pos.Y+h, g.board.Size().Y-1) // bottom wall correction
+	for y := max(0, p.Pos.Y); y < yn; y++ {
+		if g.board.Full(y) {
+			lines = append(lines, y)
+		}
+	}
+	g.lines = lines
+	g.score.Last = Clear{Piece: p.ID, Spin: spin, Lines: len(lines)}
+	if len(lines) > 0 {
+		g.state = StateClearing
+		return
+	}
+	g.score.NewLines(g.score.Last)
+	g.spawn()
+}
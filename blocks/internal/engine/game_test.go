@@ -0,0 +1,188 @@
+package engine
+
+import (
+	"image"
+	"reflect"
+	"testing"
+)
+
+func TestGameClearLines(t *testing.T) {
+	var g Game
+	g.Start(Config{})
+	boardFromString(&g.board,
+		`#..........# #..........# #.....Z....# #ZZZ.ZZZZZZ# #ZZZ.ZZZZZ.# #ZZZ.ZZZZZZ# ############`)
+	g.current = Piece{Shape: Tetrominoes[I], Pos: image.Pt(2, 0), Rot: Rot90}
+
+	g.Step(DropHard, 0)
+	if got, want := g.State(), StateClearing; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+	if got, want := g.Lines(), []int{3, 5}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+	if g.Current() != nil {
+		t.Fatal("unexpected current piece while clearing lines")
+	}
+
+	g.Step(None, 0)
+	if got, want := g.State(), StateRunning; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+	got := g.Board().String()
+	want := boardString(`#..........# #..........# #..........# #..........# #...I.Z....# #ZZZIZZZZZ.# ############`)
+	if got != want {
+		t.Errorf("got %q; want %q", got, want)
+	}
+	sc := g.Score()
+	if got, want := sc.Clears, [4]int{0, 1, 0, 0}; got != want {
+		t.Errorf("got %v; want %v", got, want)
+	}
+	// 100 points for 2 lines and 2 cells hard dropped.
+	if got, want := sc.Total, 100+2; got != want {
+		t.Errorf("got %d; want %d", got, want)
+	}
+}
+
+func TestGameOver(t *testing.T) {
+	var g Game
+	g.Start(Config{})
+	boardFromString(&g.board,
+		`#....X.....# #....X.....# #....X.....# #....X.....# ############`)
+	g.Step(DropHard, 0)
+	if got, want := g.State(), StateOver; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+}
+
+func TestGameGravity(t *testing.T) {
+	var g Game
+	g.Start(Config{Level: 5})
+	y := g.current.Pos.Y
+	d := g.Gravity()
+	g.Step(None, d-1)
+	if got, want := g.current.Pos.Y, y; got != want {
+		t.Errorf("got %d; want %d", got, want)
+	}
+	g.Step(None, 2*d+1)
+	if got, want := g.current.Pos.Y, y+3; got != want {
+		t.Errorf("got %d; want %d", got, want)
+	}
+}
+
+func TestGameHold(t *testing.T) {
+	var g Game
+	g.Start(Config{Seed: 1})
+	first, next := g.Current().ID, g.Next().ID
+	if g.Hold() != nil {
+		t.Fatal("unexpected hold piece")
+	}
+
+	g.Step(MoveLeft, 0)
+	g.Step(Hold, 0)
+	if got, want := g.Hold().ID, first; got != want {
+		t.Fatalf("hold: got %v; want %v", got, want)
+	}
+	if got, want := g.Current().ID, next; got != want {
+		t.Fatalf("current: got %v; want %v", got, want)
+	}
+	// Only once per piece.
+	g.Step(Hold, 0)
+	if got, want := g.Current().ID, next; got != want {
+		t.Fatalf("current: got %v; want %v", got, want)
+	}
+
+	g.Step(DropHard, 0)
+	if !g.CanHold() {
+		t.Fatal("cannot hold the new piece")
+	}
+	g.Step(Hold, 0)
+	p := g.Current()
+	if got, want := p.ID, first; got != want {
+		t.Fatalf("current: got %v; want %v", got, want)
+	}
+	if got, want := p.Pos.X, (g.Board().Size().X-p.Width)/2; got != want {
+		t.Errorf("position: got %d; want %d", got, want)
+	}
+}
+
+func TestGameGhost(t *testing.T) {
+	var g Game
+	g.Start(Config{})
+	boardFromString(&g.board,
+		`#..........# #..........# #..........# #..........# #....X.....# #..........# ############`)
+	g.current = Piece{Shape: Tetrominoes[O], Pos: image.Pt(5, 0)}
+	p := g.Ghost()
+	if got, want := p.Pos, image.Pt(5, 2); got != want {
+		t.Errorf("got %v; want %v", got, want)
+	}
+	g.current.Pos.X = 6
+	p = g.Ghost()
+	if got, want := p.Pos, image.Pt(6, 4); got != want {
+		t.Errorf("got %v; want %v", got, want)
+	}
+	if got, want := g.current.Pos, image.Pt(6, 0); got != want {
+		t.Errorf("got %v; want %v", got, want)
+	}
+}
+
+func TestGameQueue(t *testing.T) {
+	// The pieces sequence does not depend on the queue length.
+	var ref Game
+	ref.Start(Config{Seed: 1})
+	var want []PieceID
+	for i := 0; i < 10; i++ {
+		want = append(want, ref.Current().ID)
+		ref.Step(Hold, 0)
+		ref.Step(DropHard, 0)
+	}
+	for _, n := range []int{0, 3, MaxPreviews, 10} {
+		var g Game
+		g.Start(Config{Seed: 1, Previews: n})
+		if got, want := len(g.Queue()), g.Config().Previews; got != want || want < 1 || want > MaxPreviews {
+			t.Fatalf("previews=%d: got %d; want %d", n, got, want)
+		}
+		var got []PieceID
+		for i := 0; i < 10; i++ {
+			got = append(got, g.Current().ID)
+			if q := g.Queue(); len(q) > 1 && g.Next() != &q[0] {
+				t.Fatal("next piece is not the first in the queue")
+			}
+			g.Step(Hold, 0)
+			g.Step(DropHard, 0)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("previews=%d: got %v; want %v", n, got, want)
+		}
+	}
+}
+
+func TestGameBoardSize(t *testing.T) {
+	for _, tc := range []struct {
+		cols, rows, hidden int
+		size               image.Point
+		cfg                Config
+	}{
+		{0, 0, 0, image.Pt(Cols+2, Rows+HiddenRows+1), Config{Cols: Cols, Rows: Rows, Hidden: HiddenRows}},
+		{12, 30, 2, image.Pt(14, 33), Config{Cols: 12, Rows: 30, Hidden: 2}},
+		{1, 100, 9, image.Pt(MinCols+2, MaxRows+MaxHidden+1), Config{Cols: MinCols, Rows: MaxRows, Hidden: MaxHidden}},
+	} {
+		var g Game
+		g.Start(Config{Cols: tc.cols, Rows: tc.rows, Hidden: tc.hidden})
+		b := g.Board()
+		if got, want := b.Size(), tc.size; got != want {
+			t.Errorf("got %v; want %v", got, want)
+		}
+		if got, want := b.Hidden(), tc.cfg.Hidden; got != want {
+			t.Errorf("got %d hidden rows; want %d", got, want)
+		}
+		cfg := g.Config()
+		if cfg.Cols != tc.cfg.Cols || cfg.Rows != tc.cfg.Rows || cfg.Hidden != tc.cfg.Hidden {
+			t.Errorf("got %dx%d+%d; want %dx%d+%d", cfg.Cols, cfg.Rows, cfg.Hidden, tc.cfg.Cols, tc.cfg.Rows, tc.cfg.Hidden)
+		}
+		// The piece spawns at the top of the visible playfield.
+		p := g.Current()
+		if got, want := p.Pos.Y+p.top(), b.Hidden(); got != want {
+			t.Errorf("got piece at line %d; want %d", got, want)
+		}
+	}
+}
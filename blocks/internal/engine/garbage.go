@@ -0,0 +1,64 @@
+package engine
+
+// CheeseLines is the default number of garbage lines of the Cheese Race mode.
+const CheeseLines = 10
+
+// AddGarbage pushes n garbage lines at the bottom of the board, moving
+// the stack and the current piece up. Each line has a single hole, which
+// changes column from one line to the next with the probability set by
+// the game Messiness. The game is over if blocks are pushed out of the board.
+func (g *Game) AddGarbage(n int) {
+	if g.state != StateRunning {
+		return
+	}
+	if g.pushGarbage(n) {
+		g.state = StateOver
+		return
+	}
+	p := &g.current
+	for !p.Fits(&g.board) {
+		if p.Pos.Y+p.top() <= 0 {
+			g.state = StateOver
+			return
+		}
+		p.Pos.Y--
+	}
+	g.lowest = min(g.lowest, p.Pos.Y)
+	g.ground()
+}
+
+// pushGarbage pushes n garbage lines and reports whether or not blocks
+// were pushed out of the board.
+func (g *Game) pushGarbage(n int) (out bool) {
+	cols := g.board.Size().X - 2
+	cell := func(x int) Cell {
+		if x == g.hole {
+			return Empty
+		}
+		return Garbage
+	}
+	for i := 0; i < n; i++ {
+		if g.hole == 0 || g.garbage.Float64() < g.config.Messiness {
+			g.hole = 1 + g.garbage.Intn(cols)
+		}
+		if g.board.PushLine(cell) {
+			out = true
+		}
+	}
+	return
+}
+
+// GarbageLines returns the number of lines with garbage cells left on the board.
+func (g *Game) GarbageLines() int {
+	var n int
+	sz := g.board.Size()
+	for y := 0; y < sz.Y-1; y++ {
+		for x := 1; x < sz.X-1; x++ {
+			if g.board.Get(x, y) == Garbage {
+				n++
+				break
+			}
+		}
+	}
+	return n
+}
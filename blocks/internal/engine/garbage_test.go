@@ -0,0 +1,82 @@
+package engine
+
+import (
+	"image"
+	"testing"
+)
+
+func TestAddGarbage(t *testing.T) {
+	for _, tc := range []struct {
+		messiness float64
+		moves     bool // whether or not the hole changes column
+	}{
+		{0, false},
+		{1, true},
+	} {
+		var g Game
+		g.Start(Config{Seed: 1, Messiness: tc.messiness})
+		g.AddGarbage(Rows / 2)
+		b := g.Board()
+		holes := make(map[int]bool)
+		for y := b.Size().Y - 2; y > b.Size().Y-2-Rows/2; y-- {
+			var n int
+			for x := 1; x <= Cols; x++ {
+				switch b.Get(x, y) {
+				case Empty:
+					holes[x] = true
+				case Garbage:
+					n++
+				}
+			}
+			if n != Cols-1 {
+				t.Fatalf("messiness %v: got %d garbage cells at line %d; want %d", tc.messiness, n, y, Cols-1)
+			}
+		}
+		if got := len(holes) > 1; got != tc.moves {
+			t.Errorf("messiness %v: got %d hole columns", tc.messiness, len(holes))
+		}
+		if g.State() != StateRunning {
+			t.Fatalf("messiness %v: got %v; want %v", tc.messiness, g.State(), StateRunning)
+		}
+	}
+}
+
+func TestAddGarbagePushPiece(t *testing.T) {
+	var g Game
+	g.Start(Config{Seed: 1})
+	g.current = Piece{Shape: Tetrominoes[I], Pos: image.Pt(3, 17), Rot: Rot90}
+	// The current piece is moved up by the garbage.
+	g.AddGarbage(2)
+	if got, want := g.State(), StateRunning; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+	if got, want := g.Current().Pos.Y, 15; got != want {
+		t.Errorf("got %d; want %d", got, want)
+	}
+	// Pushing blocks out of the board ends the game.
+	g.AddGarbage(Rows + HiddenRows)
+	if got, want := g.State(), StateOver; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+}
+
+func TestAddGarbageClearing(t *testing.T) {
+	var g Game
+	g.Start(Config{})
+	boardFromString(&g.board,
+		`#..........# #..........# #.....Z....# #ZZZ.ZZZZZZ# #ZZZ.ZZZZZ.# #ZZZ.ZZZZZZ# ############`)
+	g.current = Piece{Shape: Tetrominoes[I], Pos: image.Pt(2, 0), Rot: Rot90}
+	g.Step(DropHard, 0)
+	if got, want := g.State(), StateClearing; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+	// No garbage is added while the lines are being cleared.
+	want := g.Board().String()
+	g.AddGarbage(2)
+	if got := g.Board().String(); got != want {
+		t.Errorf("got %q; want %q", got, want)
+	}
+	if got, want := g.State(), StateClearing; got != want {
+		t.Errorf("got %v; want %v", got, want)
+	}
+}
@@ -0,0 +1,48 @@
+package engine
+
+// LockReset defines when the lock delay of a piece on the ground is restarted.
+type LockReset uint8
+
+const (
+	LockResetMove     LockReset = iota // 15 moves
+	LockResetInfinite                  // Infinite
+	LockResetStep                      // Step
+	LockReset_
+)
+
+// lockMoves is the number of moves or rotations restarting the lock delay
+// with LockResetMove, until the piece reaches a lower line.
+const lockMoves = 15
+
+// ground starts or stops the lock delay depending on whether or not
+// the current piece is on the ground.
+func (g *Game) ground() {
+	if g.config.LockDelay == 0 {
+		return
+	}
+	p := &g.current
+	if p.Pos.Y > g.lowest {
+		// Reaching a lower line restarts the delay and the moves count.
+		g.lowest = p.Pos.Y
+		g.lockLeft = g.config.LockDelay
+		g.resets = 0
+	}
+	g.locking = !g.fits(0, 1)
+}
+
+// resetLock restarts the lock delay after the current piece was moved or rotated.
+func (g *Game) resetLock() {
+	if !g.locking {
+		return
+	}
+	switch g.config.LockReset {
+	case LockResetMove:
+		if g.resets >= lockMoves {
+			return
+		}
+		g.resets++
+	case LockResetStep:
+		return
+	}
+	g.lockLeft = g.config.LockDelay
+}
@@ -0,0 +1,69 @@
+package engine
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLockDelay(t *testing.T) {
+	const delay = 500 * time.Millisecond
+	for _, tc := range []struct {
+		reset LockReset
+		left  time.Duration // remaining delay after a move
+		moves bool          // whether or not moving forever prevents locking
+	}{
+		{LockResetMove, delay, false},
+		{LockResetInfinite, delay, true},
+		{LockResetStep, 200 * time.Millisecond, false},
+	} {
+		t.Run(tc.reset.String(), func(t *testing.T) {
+			var g Game
+			g.Start(Config{Seed: 1, LockDelay: delay, LockReset: tc.reset})
+			locked := func() bool {
+				size := g.Board().Size()
+				for x := 1; x < size.X-1; x++ {
+					if g.Board().Get(x, size.Y-2) != Empty {
+						return true
+					}
+				}
+				return false
+			}
+			for i := 0; i < Rows+HiddenRows; i++ {
+				g.Step(DropSoft, 0)
+			}
+			if locked() {
+				t.Fatal("soft drop locked the piece")
+			}
+			if _, ok := g.Locking(); !ok {
+				t.Fatal("piece not on the ground")
+			}
+
+			g.Step(None, 300*time.Millisecond)
+			if got, _ := g.Locking(); got != 200*time.Millisecond {
+				t.Fatalf("got %v; want %v", got, 200*time.Millisecond)
+			}
+			g.Step(MoveLeft, 0)
+			if got, _ := g.Locking(); got != tc.left {
+				t.Fatalf("got %v; want %v", got, tc.left)
+			}
+			if tc.reset == LockResetStep {
+				g.Step(None, tc.left)
+				if !locked() {
+					t.Fatal("piece not locked")
+				}
+				return
+			}
+
+			for i := 0; i < 2*lockMoves; i++ {
+				a := MoveLeft
+				if i%2 == 0 {
+					a = MoveRight
+				}
+				g.Step(a, 100*time.Millisecond)
+			}
+			if got, want := locked(), !tc.moves; got != want {
+				t.Errorf("got %v; want %v", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package engine
+
+import (
+	"strings"
+	"time"
+)
+
+// Mode is a game mode, defining when a game is complete.
+type Mode uint8
+
+const (
+	ModeMarathon Mode = iota // Marathon
+	ModeSprint               // Sprint
+	ModeUltra                // Ultra
+	ModeCheese               // Cheese Race
+	ModeSurvival             // Survival
+	Mode_
+)
+
+// ParseMode returns the mode named s, regardless of case and with or without spaces.
+func ParseMode(s string) (Mode, bool) {
+	for m := Mode(0); m < Mode_; m++ {
+		name := m.String()
+		if strings.EqualFold(s, name) || strings.EqualFold(s, strings.ReplaceAll(name, " ", "")) {
+			return m, true
+		}
+	}
+	return 0, false
+}
+
+// SprintLines is the number of lines to be cleared in the Sprint mode.
+const SprintLines = 40
+
+// UltraTimes are the available durations of the Ultra mode.
+var UltraTimes = [...]time.Duration{2 * time.Minute, 3 * time.Minute}
+
+// Intervals between two garbage lines in the Survival mode, the first one
+// shrinking by a tenth every SurvivalPeriod of game time down to the last one.
+const (
+	SurvivalStart  = 10 * time.Second
+	SurvivalMin    = time.Second
+	SurvivalPeriod = 30 * time.Second
+)
+
+// goal returns the state of the game once the goal of its mode is reached,
+// or StateRunning.
+func (g *Game) goal() State {
+	switch g.config.Mode {
+	case ModeSprint:
+		if g.score.Lines >= SprintLines {
+			return StateDone
+		}
+	case ModeUltra:
+		if g.score.Time >= g.config.TimeLimit {
+			return StateTimeUp
+		}
+	case ModeCheese:
+		if g.GarbageLines() == 0 {
+			return StateDone
+		}
+	}
+	return StateRunning
+}
+
+// TimeLeft returns the remaining game time and whether or not it is limited.
+func (g *Game) TimeLeft() (time.Duration, bool) {
+	if g.config.Mode != ModeUltra {
+		return 0, false
+	}
+	return g.config.TimeLimit - g.score.Time, true
+}
+
+// NextGarbage returns the time left before the next garbage line
+// and whether or not garbage lines are rising.
+func (g *Game) NextGarbage() (time.Duration, bool) {
+	if g.config.Mode != ModeSurvival {
+		return 0, false
+	}
+	return g.tide, true
+}
+
+// tideInterval returns the interval before the next garbage line in the Survival mode.
+func (g *Game) tideInterval() time.Duration {
+	d := SurvivalStart
+	for t := g.score.Time; t >= SurvivalPeriod && d > SurvivalMin; t -= SurvivalPeriod {
+		d -= d / 10
+	}
+	if d < SurvivalMin {
+		return SurvivalMin
+	}
+	return d
+}
+
+// rise elapses dt and adds the time played to the game time, adding a garbage
+// line every time the tide interval elapses in the Survival mode.
+func (g *Game) rise(dt time.Duration) {
+	if g.config.Mode != ModeSurvival {
+		g.score.Time += dt - g.elapse(dt)
+		return
+	}
+	for g.state == StateRunning {
+		d := g.tide
+		if dt < d {
+			d = dt
+		}
+		d -= g.elapse(d)
+		g.score.Time += d
+		g.tide -= d
+		dt -= d
+		if g.tide > 0 || g.state != StateRunning {
+			// The line is added once the game runs again.
+			return
+		}
+		g.AddGarbage(1)
+		g.tide = g.tideInterval()
+	}
+}
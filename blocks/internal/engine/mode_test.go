@@ -0,0 +1,115 @@
+package engine
+
+import (
+	"image"
+	"testing"
+	"time"
+)
+
+func TestModeSprint(t *testing.T) {
+	var g Game
+	g.Start(Config{Mode: ModeSprint})
+	boardFromString(&g.board,
+		`#..........# #..........# #.....Z....# #ZZZ.ZZZZZZ# #ZZZ.ZZZZZ.# #ZZZ.ZZZZZZ# ############`)
+	g.current = Piece{Shape: Tetrominoes[I], Pos: image.Pt(2, 0), Rot: Rot90}
+	g.score.Lines = SprintLines - 2
+
+	g.Step(None, time.Second)
+	g.Step(DropHard, 0)
+	g.Step(None, time.Second)
+	if got, want := g.State(), StateDone; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+	if g.Current() != nil {
+		t.Fatal("unexpected current piece once done")
+	}
+	// The time spent clearing lines is not accounted for.
+	if got, want := g.Score().Time, time.Second; got != want {
+		t.Errorf("got %v; want %v", got, want)
+	}
+}
+
+func TestModeUltra(t *testing.T) {
+	var g Game
+	g.Start(Config{Mode: ModeUltra, TimeLimit: 5 * time.Second})
+	g.Step(None, 3*time.Second)
+	if got, want := g.State(), StateRunning; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+	if left, _ := g.TimeLeft(); left != 2*time.Second {
+		t.Fatalf("got %v; want %v", left, 2*time.Second)
+	}
+	g.Step(None, 3*time.Second)
+	if got, want := g.State(), StateTimeUp; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+	if got, want := g.Score().Time, 5*time.Second; got != want {
+		t.Errorf("got %v; want %v", got, want)
+	}
+}
+
+func TestModeCheese(t *testing.T) {
+	var g Game
+	g.Start(Config{Mode: ModeCheese, Garbage: 2, Seed: 1})
+	if got, want := g.GarbageLines(), 2; got != want {
+		t.Fatalf("got %d; want %d", got, want)
+	}
+	boardFromString(&g.board,
+		`#..........# #..........# #..........# #XXXX.XXXXX# #XXXX.XXXXX# ############`)
+	g.current = Piece{Shape: Tetrominoes[I], Pos: image.Pt(3, 0), Rot: Rot90}
+
+	g.Step(DropHard, 0)
+	g.Step(None, 0)
+	if got, want := g.State(), StateDone; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+}
+
+func TestModeSurvival(t *testing.T) {
+	var g Game
+	g.Start(Config{Mode: ModeSurvival})
+	g.Step(None, SurvivalStart-time.Second)
+	if got, want := g.GarbageLines(), 0; got != want {
+		t.Fatalf("got %d; want %d", got, want)
+	}
+	g.Step(None, 2*time.Second)
+	if got, want := g.GarbageLines(), 1; got != want {
+		t.Fatalf("got %d; want %d", got, want)
+	}
+	if left, _ := g.NextGarbage(); left != SurvivalStart-time.Second {
+		t.Errorf("got %v; want %v", left, SurvivalStart-time.Second)
+	}
+	// The interval shrinks over time.
+	for _, tc := range []struct {
+		t, want time.Duration
+	}{
+		{SurvivalPeriod - 1, SurvivalStart},
+		{SurvivalPeriod, SurvivalStart * 9 / 10},
+		{2 * SurvivalPeriod, SurvivalStart * 81 / 100},
+		{time.Hour, SurvivalMin},
+	} {
+		g.score.Time = tc.t
+		if got := g.tideInterval(); got != tc.want {
+			t.Errorf("%v: got %v; want %v", tc.t, got, tc.want)
+		}
+	}
+}
+
+func TestParseMode(t *testing.T) {
+	for _, tc := range []struct {
+		s    string
+		mode Mode
+		ok   bool
+	}{
+		{"marathon", ModeMarathon, true},
+		{"Cheese Race", ModeCheese, true},
+		{"cheeserace", ModeCheese, true},
+		{"SURVIVAL", ModeSurvival, true},
+		{"cheese", 0, false},
+	} {
+		m, ok := ParseMode(tc.s)
+		if m != tc.mode || ok != tc.ok {
+			t.Errorf("%q: got %v, %v; want %v, %v", tc.s, m, ok, tc.mode, tc.ok)
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package engine
+
+import "image"
+
+type PieceID uint8
+
+const (
+	// Tetrominoes.
+	I PieceID = iota
+	J
+	L
+	O
+	S
+	T
+	Z
+	// Pentominoes, the R suffix denoting the reflected ones.
+	F5
+	F5R // F5'
+	I5
+	L5
+	L5R // L5'
+	N5
+	N5R // N5'
+	P5
+	P5R // P5'
+	T5
+	U5
+	V5
+	W5
+	X5
+	Y5
+	Y5R // Y5'
+	Z5
+	Z5R // Z5'
+	// Tiny pieces.
+	I2
+	I3
+	L3
+	// Big tetrominoes.
+	BigI // BIG I
+	BigJ // BIG J
+	BigL // BIG L
+	BigO // BIG O
+	BigS // BIG S
+	BigT // BIG T
+	BigZ // BIG Z
+	// Custom is the first user defined piece, the next ones following it.
+	Custom // CUSTOM
+)
+
+type Rotation uint8
+
+// Clockwise piece rotations.
+const (
+	Rot0 Rotation = iota
+	Rot90
+	Rot180
+	Rot270
+)
+
+func (r Rotation) Next() Rotation {
+	return (r + 1) % 4
+}
+
+func (r Rotation) Prev() Rotation {
+	return (r + 3) % 4
+}
+
+// Shape defines a piece.
+type Shape struct {
+	ID PieceID
+	// Data is the square matrix of the piece cells, including its padding.
+	Data   [][]bool
+	Width  int // width without padding
+	Height int // height without padding
+	// SpawnOffset is added to the default spawn position.
+	SpawnOffset image.Point
+}
+
+// newShape returns the shape defined by rows, where filled cells are set with X.
+func newShape(id PieceID, rows ...string) Shape {
+	s := Shape{ID: id}
+	minX, maxX, minY, maxY := len(rows), -1, len(rows), -1
+	for y, row := range rows {
+		line := make([]bool, len(row))
+		for x, c := range row {
+			if c != 'X' {
+				continue
+			}
+			line[x] = true
+			minX, maxX = min(minX, x), max(maxX, x)
+			minY, maxY = min(minY, y), max(maxY, y)
+		}
+		s.Data = append(s.Data, line)
+	}
+	s.Width = maxX - minX + 1
+	s.Height = maxY - minY + 1
+	return s
+}
+
+// Tetrominoes is the standard piece set.
+var Tetrominoes = []Shape{
+	I: newShape(I,
+		"....",
+		"XXXX",
+		"....",
+		"....",
+	),
+	J: newShape(J,
+		"...",
+		"XXX",
+		"..X",
+	),
+	L: newShape(L,
+		"...",
+		"XXX",
+		"X..",
+	),
+	O: newShape(O,
+		"XX",
+		"XX",
+	),
+	S: newShape(S,
+		"...",
+		".XX",
+		"XX.",
+	),
+	T: newShape(T,
+		"...",
+		"XXX",
+		".X.",
+	),
+	Z: newShape(Z,
+		"...",
+		"XX.",
+		".XX",
+	),
+}
+
+// Piece is a shape positioned on the board.
+type Piece struct {
+	Shape
+	// Pos is the position of the top left corner of the piece,
+	// including its padding.
+	Pos image.Point
+	Rot Rotation
+}
+
+// Dims returns the full dimensions of the piece,
+// including its padding.
+func (p *Piece) Dims() (fullWidth, fullHeight int) {
+	return len(p.Data[0]), len(p.Data)
+}
+
+// Walk calls fn for every filled cell of the piece, with (x, y) its position
+// relative to the piece position and (sx, sy) its position in the unrotated shape,
+// until fn returns true.
+func (p *Piece) Walk(fn func(x, y, sx, sy int) bool) {
+	xn, yn := p.Dims()
+	for y := 0; y < yn; y++ {
+		for x := 0; x < xn; x++ {
+			xx, yy := x, y
+			sx, sy := x, y
+			switch p.Rot {
+			case Rot90:
+				sx, sy = x, yn-1-y
+				xx, yy = y, x
+			case Rot180:
+				sx, sy = xn-1-x, yn-1-y
+			case Rot270:
+				sx, sy = xn-1-x, y
+				xx, yy = y, x
+			}
+			if !p.Data[sy][sx] {
+				continue
+			}
+			if fn(xx, yy, sx, sy) {
+				return
+			}
+		}
+	}
+}
+
+// Spawn moves the piece to its spawn position at the top middle of the board.
+func (p *Piece) Spawn(b *Board) {
+	cols := b.Size().X
+	p.Pos.X = (cols - p.Width) / 2
+	// Skip first empty lines so that the piece gets displayed at the top edge.
+	p.Pos.Y = b.Hidden() - p.top()
+	p.Pos = p.Pos.Add(p.SpawnOffset)
+}
+
+// Fits reports whether or not all the piece cells are on the board
+// and do not collide with anything on it.
+func (p *Piece) Fits(b *Board) (ok bool) {
+	ok = true
+	sz := b.Size()
+	p.Walk(func(x, y, _, _ int) bool {
+		// Pieces rotating far from their cells may go past the walls.
+		x, y = p.Pos.X+x, p.Pos.Y+y
+		if x < 0 || y < 0 || x >= sz.X || y >= sz.Y || b.Get(x, y) != Empty {
+			ok = false
+			return true
+		}
+		return false
+	})
+	return
+}
+
+// top returns the first filled line of the piece in its current rotation.
+func (p *Piece) top() int {
+	top := -1
+	p.Walk(func(_, y, _, _ int) bool {
+		if top < 0 || y < top {
+			top = y
+		}
+		return false
+	})
+	return top
+}
+
+func max(a, b int) int {
+	if a < b {
+		return b
+	}
+	return a
+}
+
+func min(a, b int) int {
+	if a > b {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,172 @@
+package engine
+
+import (
+	"fmt"
+	"image"
+	"strings"
+	"testing"
+)
+
+func boardString(s string) string {
+	return strings.Join(
+		strings.Split(s, " "),
+		"\n",
+	) + "\n"
+}
+
+// Populate b with cells from data, # for walls, X for garbage, piece IDs or * for blocks.
+func boardFromString(b *Board, data string) {
+	rows := strings.Split(data, " ")
+	b.Init(len(rows[0])-2, len(rows)-HiddenRows-1, HiddenRows)
+	for y, row := range rows {
+		for x, c := range row {
+			switch c {
+			case '#':
+				b.Set(x, y, Wall)
+			case '.':
+				b.Set(x, y, Empty)
+			case 'X':
+				b.Set(x, y, Garbage)
+			default:
+				cell := Block + Cell(len(Tetrominoes))
+				for id := range Tetrominoes {
+					if PieceID(id).String() == string(c) {
+						cell = Block + Cell(id)
+					}
+				}
+				b.Set(x, y, cell)
+			}
+		}
+	}
+}
+
+func TestPieceWalk(t *testing.T) {
+	type tcase struct {
+		index PieceID
+		rot   Rotation
+		drawn string
+	}
+	for _, tc := range []tcase{
+		{
+			index: I,
+			rot:   Rot0,
+			drawn: `#....# #IIII# #....# #....# ######`,
+		},
+		{
+			index: I,
+			rot:   Rot90,
+			drawn: `#..I.# #..I.# #..I.# #..I.# ######`,
+		},
+		{
+			index: T,
+			rot:   Rot0,
+			drawn: `#....# #TTT.# #.T..# #....# ######`,
+		},
+		{
+			index: T,
+			rot:   Rot90,
+			drawn: `#.T..# #TT..# #.T..# #....# ######`,
+		},
+		{
+			index: T,
+			rot:   Rot180,
+			drawn: `#.T..# #TTT.# #....# #....# ######`,
+		},
+		{
+			index: T,
+			rot:   Rot270,
+			drawn: `#.T..# #.TT.# #.T..# #....# ######`,
+		},
+	} {
+		t.Run(fmt.Sprintf("%v%d", tc.index, tc.rot), func(t *testing.T) {
+			var b Board
+			b.Init(4, 3, HiddenRows)
+			p := Piece{Shape: Tetrominoes[tc.index], Pos: image.Pt(1, 0), Rot: tc.rot}
+			p.Walk(func(x, y, _, _ int) bool {
+				b.Set(p.Pos.X+x, p.Pos.Y+y, Block+Cell(p.ID))
+				return false
+			})
+			got := b.String()
+			want := boardString(tc.drawn)
+			if got != want {
+				t.Errorf("got %q; want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestPieceFits(t *testing.T) {
+	type fits struct {
+		left, right, down bool
+	}
+	type tcase struct {
+		index PieceID
+		pos   image.Point
+		board []string
+		res   []fits
+	}
+	for _, tc := range []tcase{
+		{
+			index: I,
+			pos:   image.Pt(1, 0),
+			board: []string{
+				`#......# #......# #......# ########`,
+				`#......# #....X.# #......# ########`,
+				`#......# #......# #X....X# ########`,
+			},
+			res: []fits{
+				{false, true, true},
+				{false, false, true},
+				{false, true, false},
+			},
+		},
+		{
+			index: O,
+			pos:   image.Pt(3, 1),
+			board: []string{
+				`#......# #......# #......# ########`,
+				`#......# #.X....# #......# ########`,
+			},
+			res: []fits{
+				{true, true, false},
+				{false, true, false},
+			},
+		},
+	} {
+		t.Run(tc.index.String(), func(t *testing.T) {
+			for bi, bs := range tc.board {
+				t.Run(fmt.Sprintf("%d", bi), func(t *testing.T) {
+					var b Board
+					boardFromString(&b, bs)
+					p := Piece{Shape: Tetrominoes[tc.index], Pos: tc.pos}
+					if !p.Fits(&b) {
+						t.Fatalf("piece does not fit at %v", p.Pos)
+					}
+					var got, want bool
+
+					p.Pos.X--
+					got = p.Fits(&b)
+					want = tc.res[bi].left
+					if got != want {
+						t.Errorf("left: got %v; want %v", got, want)
+					}
+					p.Pos.X += 2
+					got = p.Fits(&b)
+					want = tc.res[bi].right
+					if got != want {
+						t.Errorf("right: got %v; want %v", got, want)
+					}
+					p.Pos.X--
+
+					p.Pos.Y++
+					got = p.Fits(&b)
+					want = tc.res[bi].down
+					if got != want {
+						t.Errorf("down: got %v; want %v", got, want)
+					}
+					p.Pos.Y--
+				})
+			}
+		})
+	}
+}
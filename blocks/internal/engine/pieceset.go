@@ -0,0 +1,308 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"strings"
+)
+
+// PieceSet identifies the shapes of the pieces dealt in a game.
+type PieceSet uint8
+
+const (
+	PiecesStandard    PieceSet = iota // Standard
+	PiecesPentominoes                 // Pentominoes
+	PiecesTiny                        // Tiny
+	PiecesBig                         // Big
+	PiecesCustom                      // Custom
+	PieceSet_
+)
+
+// ParsePieceSet returns the piece set named s, regardless of case.
+func ParsePieceSet(s string) (PieceSet, bool) {
+	for set := PieceSet(0); set < PieceSet_; set++ {
+		if strings.EqualFold(s, set.String()) {
+			return set, true
+		}
+	}
+	return 0, false
+}
+
+// Shapes returns the shapes of the set, indexed by the randomizer picks.
+// Unknown sets default to the standard one, as well as the custom one
+// whose shapes are set in the game config.
+func (s PieceSet) Shapes() []Shape {
+	switch s {
+	case PiecesPentominoes:
+		return Pentominoes
+	case PiecesTiny:
+		return Tiny
+	case PiecesBig:
+		return Big
+	}
+	return Tetrominoes
+}
+
+// Size returns the largest width or height of the set shapes,
+// the minimum playfield dimensions for them to fit.
+func (s PieceSet) Size() int {
+	var n int
+	for _, s := range s.Shapes() {
+		n = max(n, max(s.Width, s.Height))
+	}
+	return n
+}
+
+// Pentominoes is the set of the 18 one-sided pieces made of 5 cells.
+var Pentominoes = []Shape{
+	newShape(F5,
+		".XX",
+		"XX.",
+		".X.",
+	),
+	newShape(F5R,
+		"XX.",
+		".XX",
+		".X.",
+	),
+	newShape(I5,
+		".....",
+		".....",
+		"XXXXX",
+		".....",
+		".....",
+	),
+	newShape(L5,
+		"....",
+		"XXXX",
+		"X...",
+		"....",
+	),
+	newShape(L5R,
+		"....",
+		"XXXX",
+		"...X",
+		"....",
+	),
+	newShape(N5,
+		"....",
+		"XXX.",
+		"..XX",
+		"....",
+	),
+	newShape(N5R,
+		"....",
+		".XXX",
+		"XX..",
+		"....",
+	),
+	newShape(P5,
+		"XX.",
+		"XX.",
+		"X..",
+	),
+	newShape(P5R,
+		".XX",
+		".XX",
+		"..X",
+	),
+	newShape(T5,
+		"XXX",
+		".X.",
+		".X.",
+	),
+	newShape(U5,
+		"X.X",
+		"XXX",
+		"...",
+	),
+	newShape(V5,
+		"X..",
+		"X..",
+		"XXX",
+	),
+	newShape(W5,
+		"X..",
+		"XX.",
+		".XX",
+	),
+	newShape(X5,
+		".X.",
+		"XXX",
+		".X.",
+	),
+	newShape(Y5,
+		"....",
+		"XXXX",
+		".X..",
+		"....",
+	),
+	newShape(Y5R,
+		"....",
+		"XXXX",
+		"..X.",
+		"....",
+	),
+	newShape(Z5,
+		"XX.",
+		".X.",
+		".XX",
+	),
+	newShape(Z5R,
+		".XX",
+		".X.",
+		"XX.",
+	),
+}
+
+// Tiny is the set of the pieces made of 2 and 3 cells.
+var Tiny = []Shape{
+	newShape(I2,
+		"XX",
+		"..",
+	),
+	newShape(I3,
+		"...",
+		"XXX",
+		"...",
+	),
+	newShape(L3,
+		"XX",
+		"X.",
+	),
+}
+
+// Big is the standard set with pieces twice as large.
+var Big = func() []Shape {
+	shapes := make([]Shape, len(Tetrominoes))
+	for i, s := range Tetrominoes {
+		shapes[i] = s.scale(BigI+s.ID, 2)
+	}
+	return shapes
+}()
+
+// scale returns the shape with its cells scaled by n.
+func (s Shape) scale(id PieceID, n int) Shape {
+	rows := make([]string, n*len(s.Data))
+	for y, line := range s.Data {
+		var row []byte
+		for _, ok := range line {
+			c := byte('.')
+			if ok {
+				c = 'X'
+			}
+			for i := 0; i < n; i++ {
+				row = append(row, c)
+			}
+		}
+		for i := 0; i < n; i++ {
+			rows[n*y+i] = string(row)
+		}
+	}
+	return newShape(id, rows...)
+}
+
+// NewShape returns the custom piece made of the cells, rotating around center
+// given in half cells so that it can lie between cells, and spawning
+// at offset from the default position.
+func NewShape(id PieceID, cells []image.Point, center, offset image.Point) (Shape, error) {
+	if len(cells) == 0 {
+		return Shape{}, errors.New("no cells")
+	}
+	if (center.X-center.Y)%2 != 0 {
+		return Shape{}, fmt.Errorf("center (%v, %v) not on a cell or between 4 cells",
+			float64(center.X)/2, float64(center.Y)/2)
+	}
+	if !connected(cells) {
+		return Shape{}, errors.New("cells not connected")
+	}
+	// The shape is padded into a square whose middle is the rotation center,
+	// with the cells at their distance d in half cells from it.
+	var h int // half the square side minus one cell, in half cells
+	for _, c := range cells {
+		d := c.Mul(2).Sub(center)
+		h = max(h, max(max(d.X, -d.X), max(d.Y, -d.Y)))
+	}
+	n := h + 1
+	rows := make([][]byte, n)
+	for y := range rows {
+		rows[y] = make([]byte, n)
+		for x := range rows[y] {
+			rows[y][x] = '.'
+		}
+	}
+	for _, c := range cells {
+		d := c.Mul(2).Sub(center)
+		rows[(d.Y+h)/2][(d.X+h)/2] = 'X'
+	}
+	data := make([]string, n)
+	for y, row := range rows {
+		data[y] = string(row)
+	}
+	s := newShape(id, data...)
+	s.SpawnOffset = offset
+	return s, nil
+}
+
+// Center returns the middle of the cells in half cells, moved half a cell
+// down or right on its shortest side if need be to be a valid rotation center.
+func Center(cells []image.Point) image.Point {
+	if len(cells) == 0 {
+		return image.Point{}
+	}
+	r := image.Rectangle{Min: cells[0], Max: cells[0]}
+	for _, c := range cells {
+		r = r.Union(image.Rectangle{Min: c, Max: c.Add(image.Pt(1, 1))})
+	}
+	r.Max = r.Max.Sub(image.Pt(1, 1))
+	c := r.Min.Add(r.Max)
+	if (c.X-c.Y)%2 != 0 {
+		if r.Dx() < r.Dy() {
+			c.X++
+		} else {
+			c.Y++
+		}
+	}
+	return c
+}
+
+// connected reports whether or not all the cells are connected through their sides.
+func connected(cells []image.Point) bool {
+	todo := make(map[image.Point]bool, len(cells))
+	for _, c := range cells {
+		todo[c] = true
+	}
+	stack := []image.Point{cells[0]}
+	delete(todo, cells[0])
+	for len(stack) > 0 {
+		c := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, d := range []image.Point{{0, -1}, {1, 0}, {0, 1}, {-1, 0}} {
+			if n := c.Add(d); todo[n] {
+				delete(todo, n)
+				stack = append(stack, n)
+			}
+		}
+	}
+	return len(todo) == 0
+}
+
+// Check returns an error if the shape is too large for a board of the given
+// dimensions in any of its rotations, or does not fit at its spawn position.
+// Rotations failing on the board, for instance next to a wall, are not checked
+// as they are rejected during the game.
+func (s Shape) Check(cols, rows, hidden int) error {
+	var b Board
+	b.Init(cols, rows, hidden)
+	p := Piece{Shape: s}
+	// Rotations swap the shape width and height.
+	if n := max(s.Width, s.Height); n > min(cols, rows) {
+		return fmt.Errorf("%d cells large, too large for a %dx%d board", n, cols, rows)
+	}
+	p.Spawn(&b)
+	if !p.Fits(&b) {
+		return errors.New("does not fit at its spawn position")
+	}
+	return nil
+}
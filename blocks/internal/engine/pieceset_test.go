@@ -0,0 +1,242 @@
+package engine
+
+import (
+	"image"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPieceSets(t *testing.T) {
+	for _, tc := range []struct {
+		set   PieceSet
+		count int
+		cells []int
+	}{
+		{PiecesStandard, 7, []int{4}},
+		{PiecesPentominoes, 18, []int{5}},
+		{PiecesTiny, 3, []int{2, 3}},
+		{PiecesBig, 7, []int{16}},
+	} {
+		t.Run(tc.set.String(), func(t *testing.T) {
+			shapes := tc.set.Shapes()
+			if got, want := len(shapes), tc.count; got != want {
+				t.Fatalf("got %d shapes; want %d", got, want)
+			}
+			seen := make(map[PieceID]bool)
+			for _, s := range shapes {
+				if seen[s.ID] {
+					t.Errorf("%v: duplicate ID", s.ID)
+				}
+				seen[s.ID] = true
+				for _, row := range s.Data {
+					if len(row) != len(s.Data) {
+						t.Errorf("%v: shape is not square", s.ID)
+					}
+				}
+				var n int
+				p := Piece{Shape: s}
+				p.Walk(func(_, _, _, _ int) bool {
+					n++
+					return false
+				})
+				var ok bool
+				for _, c := range tc.cells {
+					ok = ok || n == c
+				}
+				if !ok {
+					t.Errorf("%v: got %d cells; want %v", s.ID, n, tc.cells)
+				}
+			}
+
+			var g Game
+			g.Start(Config{Seed: 1, Pieces: tc.set})
+			for i := 0; i < 100 && g.State() == StateRunning; i++ {
+				g.Step(DropHard, time.Duration(i)*time.Second)
+				if p := g.Current(); p != nil && !seen[p.ID] {
+					t.Fatalf("%v: piece not in the set", p.ID)
+				}
+			}
+		})
+	}
+}
+
+func TestParsePieceSet(t *testing.T) {
+	for set := PieceSet(0); set < PieceSet_; set++ {
+		if got, ok := ParsePieceSet(strings.ToLower(set.String())); got != set || !ok {
+			t.Errorf("got %v, %v; want %v", got, ok, set)
+		}
+	}
+	if _, ok := ParsePieceSet("huge"); ok {
+		t.Error("unexpected set")
+	}
+}
+
+func TestPieceSetBoard(t *testing.T) {
+	for set := PiecesStandard; set < PiecesCustom; set++ {
+		var g Game
+		g.Start(Config{Seed: 1, Cols: MinCols, Pieces: set})
+		// The board is widened for the largest pieces.
+		if got, want := g.Config().Cols, max(MinCols, set.Size()); got != want {
+			t.Errorf("%v: got %d columns; want %d", set, got, want)
+		}
+		for i := 0; i < 100 && g.State() == StateRunning; i++ {
+			g.Step(RotateRight, 0)
+			g.Step(DropHard, time.Duration(i)*time.Second)
+		}
+	}
+	if got, want := PiecesBig.Size(), 8; got != want {
+		t.Errorf("got %d; want %d", got, want)
+	}
+}
+
+func TestClearTier(t *testing.T) {
+	var s Score
+	s.NewLines(Clear{Piece: BigI, Lines: 6})
+	if got, want := s.Clears, [4]int{0, 0, 0, 1}; got != want {
+		t.Errorf("got %v; want %v", got, want)
+	}
+	if got, want := s.Lines, 6; got != want {
+		t.Errorf("got %d lines; want %d", got, want)
+	}
+	if got, want := s.Total, 1200; got != want {
+		t.Errorf("got %d points; want %d", got, want)
+	}
+	if got, want := (Clear{Piece: I5, Lines: 5}).String(), "TETRIS"; got != want {
+		t.Errorf("got %q; want %q", got, want)
+	}
+}
+
+func TestNewShape(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		cells  []image.Point
+		center image.Point // default center if zero
+		data   string
+		err    bool
+	}{
+		{
+			name:  "T",
+			cells: []image.Point{{1, 0}, {0, 1}, {1, 1}, {2, 1}},
+			data:  `.X. XXX ...`,
+		},
+		{
+			name:  "I",
+			cells: []image.Point{{0, 0}, {1, 0}, {2, 0}, {3, 0}},
+			data:  `.... XXXX .... ....`,
+		},
+		{
+			name:   "O",
+			cells:  []image.Point{{0, 0}, {1, 0}, {0, 1}, {1, 1}},
+			center: image.Pt(1, 1),
+			data:   `XX XX`,
+		},
+		{
+			name:   "off center",
+			cells:  []image.Point{{0, 0}, {1, 0}},
+			center: image.Pt(4, 0),
+			data:   `..... ..... XX... ..... .....`,
+		},
+		{
+			name:  "disconnected",
+			cells: []image.Point{{0, 0}, {2, 0}},
+			err:   true,
+		},
+		{
+			name:   "bad center",
+			cells:  []image.Point{{0, 0}, {1, 0}},
+			center: image.Pt(1, 0),
+			err:    true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			center := tc.center
+			if center == (image.Point{}) {
+				center = Center(tc.cells)
+			}
+			s, err := NewShape(Custom, tc.cells, center, image.Point{})
+			if tc.err {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			var rows []string
+			for _, line := range s.Data {
+				var row []byte
+				for _, ok := range line {
+					if ok {
+						row = append(row, 'X')
+					} else {
+						row = append(row, '.')
+					}
+				}
+				rows = append(rows, string(row))
+			}
+			if got, want := strings.Join(rows, " "), tc.data; got != want {
+				t.Errorf("got %q; want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestShapeCheck(t *testing.T) {
+	line := make([]image.Point, 12)
+	for i := range line {
+		line[i].X = i
+	}
+	s, err := NewShape(Custom, line, Center(line), image.Point{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Check(Cols, Rows, HiddenRows); err == nil {
+		t.Error("expected error for a too large shape")
+	}
+	if err := s.Check(MaxCols, Rows, HiddenRows); err != nil {
+		t.Error(err)
+	}
+	s.SpawnOffset.Y = Rows
+	if err := s.Check(MaxCols, Rows, HiddenRows); err == nil {
+		t.Error("expected error for a shape spawning out of the board")
+	}
+
+	// Shapes not fitting are replaced by the standard ones.
+	var g Game
+	g.Start(Config{Seed: 1, Pieces: PiecesCustom, Shapes: []Shape{s}})
+	if got, want := g.Config().Pieces, PiecesStandard; got != want {
+		t.Errorf("got %v; want %v", got, want)
+	}
+	if got, want := g.Replay().Config.Pieces, PiecesStandard; got != want {
+		t.Errorf("got replay %v; want %v", got, want)
+	}
+	g.Start(Config{Seed: 1, Pieces: PiecesCustom})
+	if got, want := g.Config().Pieces, PiecesStandard; got != want {
+		t.Errorf("got %v; want %v", got, want)
+	}
+
+	s.SpawnOffset.Y = 2
+	g.Start(Config{Seed: 1, Cols: MaxCols, Pieces: PiecesCustom, Shapes: []Shape{s}})
+	p := g.Current()
+	if got, want := p.Pos.Y+p.top(), HiddenRows+2; got != want {
+		t.Errorf("got spawn line %d; want %d", got, want)
+	}
+}
+
+// Test that pieces rotating around a center far from their cells
+// do not go past the board.
+func TestShapeRotateOffCenter(t *testing.T) {
+	cells := []image.Point{{0, 0}, {0, 1}, {0, 2}}
+	s, err := NewShape(Custom, cells, image.Pt(5, -1), image.Point{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := Config{Seed: 1, Cols: 8, Rows: 8, Rotation: RotationSRS, Pieces: PiecesCustom, Shapes: []Shape{s}}
+	if err := s.Check(cfg.Cols, cfg.Rows, HiddenRows); err != nil {
+		t.Fatal(err)
+	}
+	var g Game
+	playRandom(&g, cfg, 1000)
+}
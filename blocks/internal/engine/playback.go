@@ -0,0 +1,67 @@
+package engine
+
+import "time"
+
+// Playback plays a replay back into a game at any pace, splitting
+// its steps as needed, and seeks by playing it again from the start.
+type Playback struct {
+	Replay *Replay
+	Game   *Game
+	step   int           // index of the current step
+	into   time.Duration // time played into the current step
+	played time.Duration // duration of the steps played
+}
+
+// Start starts the game of the replay.
+func (p *Playback) Start() {
+	p.Game.Start(p.Replay.Config)
+	p.step, p.into, p.played = 0, 0, 0
+}
+
+// Time returns the position of the playback.
+func (p *Playback) Time() time.Duration {
+	return p.played + p.into
+}
+
+// Done reports whether or not all the steps were played.
+func (p *Playback) Done() bool {
+	return p.step >= len(p.Replay.Steps)
+}
+
+// Advance plays the replay for the duration d.
+func (p *Playback) Advance(d time.Duration) {
+	steps := p.Replay.Steps
+	for p.step < len(steps) {
+		s := steps[p.step]
+		left := s.Elapsed - p.into
+		if d == 0 && left > 0 {
+			return
+		}
+		if d < left {
+			left = d
+		}
+		a := None
+		if p.into == 0 {
+			a = s.Action
+		}
+		p.Game.Step(a, left)
+		p.into += left
+		d -= left
+		// The game does not play the rest of a step once it stops running,
+		// the next one clearing the lines if need be.
+		if p.into == s.Elapsed || p.Game.State() != StateRunning {
+			p.played += s.Elapsed
+			p.step++
+			p.into = 0
+		}
+	}
+}
+
+// Seek moves the playback to the time t, playing the replay again from its start
+// if t is before the current position.
+func (p *Playback) Seek(t time.Duration) {
+	if t < p.Time() {
+		p.Start()
+	}
+	p.Advance(t - p.Time())
+}
@@ -0,0 +1,127 @@
+package engine
+
+import "math/rand"
+
+// Randomizer generates the sequence of pieces.
+type Randomizer interface {
+	// Next returns the next piece.
+	Next() PieceID
+}
+
+// Random identifies a randomizer algorithm.
+type Random uint8
+
+const (
+	RandomPure  Random = iota // Random
+	RandomBag7                // 7-bag
+	RandomBag14               // 14-bag
+	RandomNES                 // NES
+	RandomTGM                 // TGM
+	Random_
+)
+
+// New returns a randomizer for n pieces using the given seed.
+func (r Random) New(seed int64, n int) Randomizer {
+	rnd := rand.New(rand.NewSource(seed))
+	switch r {
+	case RandomBag7:
+		return &bagRandom{rnd: rnd, n: n, size: n}
+	case RandomBag14:
+		return &bagRandom{rnd: rnd, n: n, size: 2 * n}
+	case RandomNES:
+		return &nesRandom{rnd: rnd, n: n, prev: -1}
+	case RandomTGM:
+		return &tgmRandom{rnd: rnd, n: n, history: [4]PieceID{Z, S, Z, S}}
+	}
+	return &pureRandom{rnd: rnd, n: n}
+}
+
+// pureRandom picks any piece with the same probability.
+type pureRandom struct {
+	rnd *rand.Rand
+	n   int
+}
+
+func (r *pureRandom) Next() PieceID {
+	return PieceID(r.rnd.Intn(r.n))
+}
+
+// bagRandom deals all the pieces of a bag containing every piece size/n times
+// in a random order before refilling it.
+//
+// https://tetris.wiki/Random_Generator
+type bagRandom struct {
+	rnd  *rand.Rand
+	n    int
+	size int
+	bag  []PieceID
+}
+
+func (r *bagRandom) Next() PieceID {
+	if len(r.bag) == 0 {
+		for i := 0; i < r.size; i++ {
+			r.bag = append(r.bag, PieceID(i%r.n))
+		}
+		r.rnd.Shuffle(len(r.bag), func(i, j int) {
+			r.bag[i], r.bag[j] = r.bag[j], r.bag[i]
+		})
+	}
+	id := r.bag[0]
+	r.bag = r.bag[1:]
+	return id
+}
+
+// nesRandom picks a piece among n+1 choices, rerolling once
+// among n if it is the extra one or the previous piece.
+//
+// https://tetris.wiki/Tetris_(NES,_Nintendo)#Randomizer
+type nesRandom struct {
+	rnd  *rand.Rand
+	n    int
+	prev int
+}
+
+func (r *nesRandom) Next() PieceID {
+	id := r.rnd.Intn(r.n + 1)
+	if id == r.n || id == r.prev {
+		id = r.rnd.Intn(r.n)
+	}
+	r.prev = id
+	return PieceID(id)
+}
+
+// tgmRandom keeps a history of the last 4 pieces and tries up to 6 times
+// to pick one not in it. The first piece is never S, Z or O
+// for sets of as many pieces as the standard one.
+//
+// https://tetris.wiki/TGM_randomizer
+type tgmRandom struct {
+	rnd     *rand.Rand
+	n       int
+	history [4]PieceID
+	started bool
+}
+
+func (r *tgmRandom) Next() PieceID {
+	var id PieceID
+	if !r.started {
+		r.started = true
+		for id = PieceID(r.rnd.Intn(r.n)); r.n == int(Z)+1 && (id == S || id == Z || id == O); {
+			id = PieceID(r.rnd.Intn(r.n))
+		}
+	} else {
+	roll:
+		for i := 0; i < 6; i++ {
+			id = PieceID(r.rnd.Intn(r.n))
+			for _, h := range r.history {
+				if h == id {
+					continue roll
+				}
+			}
+			break
+		}
+	}
+	copy(r.history[:], r.history[1:])
+	r.history[len(r.history)-1] = id
+	return id
+}
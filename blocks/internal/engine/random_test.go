@@ -0,0 +1,63 @@
+package engine
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRandomSeed(t *testing.T) {
+	for r := RandomPure; r < Random_; r++ {
+		t.Run(r.String(), func(t *testing.T) {
+			seq := func(seed int64) []PieceID {
+				rnd := r.New(seed, len(Tetrominoes))
+				ids := make([]PieceID, 100)
+				for i := range ids {
+					ids[i] = rnd.Next()
+					if ids[i] >= PieceID(len(Tetrominoes)) {
+						t.Fatalf("invalid piece %d", ids[i])
+					}
+				}
+				return ids
+			}
+			if a, b := seq(1), seq(1); !reflect.DeepEqual(a, b) {
+				t.Errorf("different sequences for the same seed: %v and %v", a, b)
+			}
+			if a, b := seq(1), seq(2); reflect.DeepEqual(a, b) {
+				t.Errorf("same sequences for different seeds: %v", a)
+			}
+		})
+	}
+}
+
+func TestRandomBag(t *testing.T) {
+	for _, r := range []Random{RandomBag7, RandomBag14} {
+		t.Run(r.String(), func(t *testing.T) {
+			n := len(Tetrominoes)
+			size := n
+			if r == RandomBag14 {
+				size *= 2
+			}
+			rnd := r.New(1, n)
+			for bag := 0; bag < 10; bag++ {
+				count := make([]int, n)
+				for i := 0; i < size; i++ {
+					count[rnd.Next()]++
+				}
+				for id, c := range count {
+					if c != size/n {
+						t.Fatalf("bag %d: got %d %v; want %d", bag, c, PieceID(id), size/n)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestRandomTGM(t *testing.T) {
+	for seed := int64(1); seed < 100; seed++ {
+		switch id := RandomTGM.New(seed, len(Tetrominoes)).Next(); id {
+		case S, Z, O:
+			t.Fatalf("seed %d: invalid first piece %v", seed, id)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package engine
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Replay is the record of a game, from which it can be played again
+// since the game is deterministic for a given config and steps.
+type Replay struct {
+	Config Config // config with the seed set
+	Steps  []ReplayStep
+}
+
+// ReplayStep records a call to Game.Step.
+type ReplayStep struct {
+	Action  Action
+	Elapsed time.Duration
+}
+
+// replayMagic identifies the replay files, its last byte being the format version.
+var replayMagic = [...]byte{'B', 'L', 'K', 'R', 1}
+
+// Limits of the replays read, way above the ones of actual games.
+const (
+	maxReplayConfig = 1 << 20 // config size in bytes
+	maxReplaySteps  = 1 << 24 // number of steps
+)
+
+var errReplayFormat = errors.New("invalid replay format")
+
+// record adds the step to the replay of the game. Consecutive steps
+// without action are merged, unless the last one left the game clearing lines.
+func (g *Game) record(a Action, dt time.Duration) {
+	if g.state != StateRunning && g.state != StateClearing {
+		return
+	}
+	steps := g.replay.Steps
+	if n := len(steps); a == None && g.merge && n > 0 && steps[n-1].Action == None {
+		steps[n-1].Elapsed += dt
+		return
+	}
+	g.replay.Steps = append(steps, ReplayStep{Action: a, Elapsed: dt})
+}
+
+// Replay returns the record of the game so far.
+func (g *Game) Replay() *Replay {
+	return &g.replay
+}
+
+// Time returns the game time at the end of the steps.
+func (r *Replay) Time() time.Duration {
+	var t time.Duration
+	for _, s := range r.Steps {
+		t += s.Elapsed
+	}
+	return t
+}
+
+// Play starts the game with the replay config and plays its first n steps.
+func (r *Replay) Play(g *Game, n int) {
+	g.Start(r.Config)
+	for _, s := range r.Steps[:n] {
+		g.Step(s.Action, s.Elapsed)
+	}
+}
+
+// WriteTo writes the replay in a compact binary format:
+// its config as JSON then its steps as an action followed by the elapsed time.
+func (r *Replay) WriteTo(w io.Writer) (n int64, err error) {
+	cfg, err := json.Marshal(r.Config)
+	if err != nil {
+		return 0, err
+	}
+	buf := make([]byte, 0, len(replayMagic)+2*binary.MaxVarintLen64+len(cfg)+len(r.Steps)*4)
+	buf = append(buf, replayMagic[:]...)
+	buf = appendUvarint(buf, uint64(len(cfg)))
+	buf = append(buf, cfg...)
+	buf = appendUvarint(buf, uint64(len(r.Steps)))
+	for _, s := range r.Steps {
+		buf = append(buf, byte(s.Action))
+		buf = appendUvarint(buf, uint64(s.Elapsed))
+	}
+	nn, err := w.Write(buf)
+	return int64(nn), err
+}
+
+// ReadFrom reads a replay written by WriteTo.
+func (r *Replay) ReadFrom(rd io.Reader) (n int64, err error) {
+	cr := &countReader{r: bufio.NewReader(rd)}
+	defer func() {
+		n = cr.n
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			err = errReplayFormat
+		}
+	}()
+	var magic [len(replayMagic)]byte
+	if _, err := io.ReadFull(cr, magic[:]); err != nil {
+		return 0, err
+	}
+	if magic != replayMagic {
+		return 0, errReplayFormat
+	}
+	size, err := binary.ReadUvarint(cr)
+	if err != nil {
+		return 0, err
+	}
+	if size > maxReplayConfig {
+		return 0, errReplayFormat
+	}
+	cfg := make([]byte, size)
+	if _, err := io.ReadFull(cr, cfg); err != nil {
+		return 0, err
+	}
+	if err := json.Unmarshal(cfg, &r.Config); err != nil {
+		return 0, fmt.Errorf("replay config: %w", err)
+	}
+	steps, err := binary.ReadUvarint(cr)
+	if err != nil {
+		return 0, err
+	}
+	if steps > maxReplaySteps {
+		return 0, errReplayFormat
+	}
+	r.Steps = r.Steps[:0]
+	for i := uint64(0); i < steps; i++ {
+		a, err := cr.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		if Action(a) > Hold {
+			return 0, errReplayFormat
+		}
+		dt, err := binary.ReadUvarint(cr)
+		if err != nil {
+			return 0, err
+		}
+		r.Steps = append(r.Steps, ReplayStep{Action: Action(a), Elapsed: time.Duration(dt)})
+	}
+	return
+}
+
+func appendUvarint(buf []byte, v uint64) []byte {
+	var b [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(b[:], v)
+	return append(buf, b[:n]...)
+}
+
+// countReader counts the bytes read from r.
+type countReader struct {
+	r *bufio.Reader
+	n int64
+}
+
+func (c *countReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+func (c *countReader) ReadByte() (byte, error) {
+	b, err := c.r.ReadByte()
+	if err == nil {
+		c.n++
+	}
+	return b, err
+}
@@ -0,0 +1,112 @@
+package engine
+
+import (
+	"bytes"
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// playRandom plays a game with random actions and elapsed times.
+func playRandom(g *Game, cfg Config, steps int) {
+	rnd := rand.New(rand.NewSource(cfg.Seed))
+	g.Start(cfg)
+	for i := 0; i < steps && g.State() != StateOver; i++ {
+		// Hard drops end the games too early when frequent.
+		a := [...]Action{None, None, None, MoveLeft, MoveRight, DropSoft, RotateLeft, RotateRight, Hold}[rnd.Intn(9)]
+		if rnd.Intn(50) == 0 {
+			a = DropHard
+		}
+		g.Step(a, time.Duration(rnd.Intn(300))*time.Millisecond)
+		if g.State() == StateClearing && rnd.Intn(2) == 0 {
+			g.ClearLines()
+		}
+	}
+}
+
+func TestReplay(t *testing.T) {
+	for _, cfg := range []Config{
+		{Seed: 1},
+		{Seed: 2, Level: 5, LockDelay: 500 * time.Millisecond, Rotation: RotationSystem_ - 1},
+		{Seed: 3, Mode: ModeSurvival, Previews: 3},
+		{Seed: 4, Mode: ModeCheese, Messiness: 0.5, Scoring: ScoringGuideline},
+	} {
+		var g Game
+		playRandom(&g, cfg, 5000)
+		r := g.Replay()
+		if len(r.Steps) >= 5000 {
+			t.Errorf("%v: steps not merged", cfg)
+		}
+
+		buf := new(bytes.Buffer)
+		if _, err := r.WriteTo(buf); err != nil {
+			t.Fatal(err)
+		}
+		var r2 Replay
+		if _, err := r2.ReadFrom(buf); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(r, &r2) {
+			t.Fatalf("%v: replay not read back", cfg)
+		}
+
+		var g2 Game
+		r2.Play(&g2, len(r2.Steps))
+		if got, want := g2.Board().String(), g.Board().String(); got != want {
+			t.Fatalf("%v: got board\n%s\nwant\n%s", cfg, got, want)
+		}
+		if got, want := g2.Score(), g.Score(); got != want {
+			t.Fatalf("%v: got score %+v; want %+v", cfg, got, want)
+		}
+	}
+}
+
+func TestReplayFormat(t *testing.T) {
+	var r Replay
+	if _, err := r.ReadFrom(bytes.NewBufferString("BLKR")); err != errReplayFormat {
+		t.Fatalf("got %v; want %v", err, errReplayFormat)
+	}
+	// Sizes too large are rejected before allocating anything.
+	buf := appendUvarint(replayMagic[:], 1<<62)
+	if _, err := r.ReadFrom(bytes.NewReader(buf)); err != errReplayFormat {
+		t.Fatalf("got %v; want %v", err, errReplayFormat)
+	}
+	buf = appendUvarint(append(replayMagic[:], 2, '{', '}'), 1<<62)
+	if _, err := r.ReadFrom(bytes.NewReader(buf)); err != errReplayFormat {
+		t.Fatalf("got %v; want %v", err, errReplayFormat)
+	}
+}
+
+func TestPlayback(t *testing.T) {
+	var g Game
+	playRandom(&g, Config{Seed: 5, Mode: ModeSurvival, LockDelay: 250 * time.Millisecond}, 5000)
+	r := g.Replay()
+	end := r.Time()
+
+	var g2 Game
+	p := Playback{Replay: r, Game: &g2}
+	p.Start()
+	// Play at a different pace than recorded.
+	for !p.Done() {
+		p.Advance(17 * time.Millisecond)
+	}
+	if got, want := g2.Board().String(), g.Board().String(); got != want {
+		t.Fatalf("got board\n%s\nwant\n%s", got, want)
+	}
+	if got, want := g2.Score(), g.Score(); got != want {
+		t.Fatalf("got score %+v; want %+v", got, want)
+	}
+
+	// Seek backwards and forwards.
+	p.Seek(end / 2)
+	mid := g2.Board().String()
+	if got, want := p.Time(), end/2; got < want {
+		t.Fatalf("got %v; want at least %v", got, want)
+	}
+	p.Seek(end)
+	p.Seek(end / 2)
+	if got := g2.Board().String(); got != mid {
+		t.Fatalf("got board\n%s\nwant\n%s", got, mid)
+	}
+}
@@ -0,0 +1,84 @@
+package engine
+
+import "image"
+
+// RotationSystem defines how pieces behave when rotated.
+type RotationSystem uint8
+
+const (
+	RotationClassic RotationSystem = iota // Classic
+	RotationSRS                           // SRS
+	RotationSystem_
+)
+
+// kick is a list of offsets to be tried in order when rotating a piece,
+// with y pointing down.
+type kick [5]image.Point
+
+// srsKicks are the kicks for the J, L, S, T and Z pieces, as well as the non standard ones,
+// indexed by their initial SRS state and rotation direction (clockwise first).
+//
+// https://tetris.wiki/Super_Rotation_System
+var srsKicks = [4][2]kick{
+	{ // 0
+		{{0, 0}, {-1, 0}, {-1, -1}, {0, 2}, {-1, 2}}, // 0->R
+		{{0, 0}, {1, 0}, {1, -1}, {0, 2}, {1, 2}},    // 0->L
+	},
+	{ // R
+		{{0, 0}, {1, 0}, {1, 1}, {0, -2}, {1, -2}}, // R->2
+		{{0, 0}, {1, 0}, {1, 1}, {0, -2}, {1, -2}}, // R->0
+	},
+	{ // 2
+		{{0, 0}, {1, 0}, {1, -1}, {0, 2}, {1, 2}},    // 2->L
+		{{0, 0}, {-1, 0}, {-1, -1}, {0, 2}, {-1, 2}}, // 2->R
+	},
+	{ // L
+		{{0, 0}, {-1, 0}, {-1, 1}, {0, -2}, {-1, -2}}, // L->0
+		{{0, 0}, {-1, 0}, {-1, 1}, {0, -2}, {-1, -2}}, // L->2
+	},
+}
+
+// srsKicksI are the kicks for the I piece.
+var srsKicksI = [4][2]kick{
+	{ // 0
+		{{0, 0}, {-2, 0}, {1, 0}, {-2, 1}, {1, -2}}, // 0->R
+		{{0, 0}, {-1, 0}, {2, 0}, {-1, -2}, {2, 1}}, // 0->L
+	},
+	{ // R
+		{{0, 0}, {-1, 0}, {2, 0}, {-1, -2}, {2, 1}}, // R->2
+		{{0, 0}, {2, 0}, {-1, 0}, {2, -1}, {-1, 2}}, // R->0
+	},
+	{ // 2
+		{{0, 0}, {2, 0}, {-1, 0}, {2, -1}, {-1, 2}}, // 2->L
+		{{0, 0}, {1, 0}, {-2, 0}, {1, 2}, {-2, -1}}, // 2->R
+	},
+	{ // L
+		{{0, 0}, {1, 0}, {-2, 0}, {1, 2}, {-2, -1}}, // L->0
+		{{0, 0}, {-2, 0}, {1, 0}, {-2, 1}, {1, -2}}, // L->2
+	},
+}
+
+// kicks returns the offsets to be tried in order when rotating the piece
+// to the given rotation.
+func (rs RotationSystem) kicks(p *Piece, to Rotation) []image.Point {
+	if rs != RotationSRS {
+		return []image.Point{{}}
+	}
+	var table *[4][2]kick
+	// The pieces spawn in the SRS state 2, except for the I one.
+	state := (p.Rot + 2) % 4
+	switch p.ID {
+	case O, BigO:
+		return []image.Point{{}}
+	case I:
+		table = &srsKicksI
+		state = p.Rot
+	default:
+		table = &srsKicks
+	}
+	dir := 0
+	if to != p.Rot.Next() {
+		dir = 1
+	}
+	return table[state][dir][:]
+}
@@ -0,0 +1,80 @@
+package engine
+
+import (
+	"image"
+	"testing"
+)
+
+func TestRotationKicks(t *testing.T) {
+	type tcase struct {
+		name   string
+		rs     RotationSystem
+		piece  Piece
+		action Action
+		board  string
+		pos    image.Point
+		rot    Rotation
+		kick   int
+	}
+	for _, tc := range []tcase{
+		{
+			name:   "classic I right wall",
+			rs:     RotationClassic,
+			piece:  Piece{Shape: Tetrominoes[I], Pos: image.Pt(4, 0), Rot: Rot90},
+			action: RotateRight,
+			board:  `#......# #......# #......# #......# #......# ########`,
+			pos:    image.Pt(4, 0),
+			rot:    Rot90,
+			kick:   -1,
+		},
+		{
+			name:   "SRS I right wall",
+			rs:     RotationSRS,
+			piece:  Piece{Shape: Tetrominoes[I], Pos: image.Pt(4, 0), Rot: Rot90},
+			action: RotateRight,
+			board:  `#......# #......# #......# #......# #......# ########`,
+			pos:    image.Pt(3, 0),
+			rot:    Rot180,
+			kick:   1,
+		},
+		{
+			name:   "SRS T obstructed",
+			rs:     RotationSRS,
+			piece:  Piece{Shape: Tetrominoes[T], Pos: image.Pt(1, 2), Rot: Rot0},
+			action: RotateLeft,
+			board:  `#......# #......# #.X....# #......# #..XXXX# ########`,
+			pos:    image.Pt(0, 2),
+			rot:    Rot270,
+			kick:   1,
+		},
+		{
+			name:   "SRS O",
+			rs:     RotationSRS,
+			piece:  Piece{Shape: Tetrominoes[O], Pos: image.Pt(5, 3), Rot: Rot0},
+			action: RotateRight,
+			board:  `#......# #......# #......# #......# #......# ########`,
+			pos:    image.Pt(5, 3),
+			rot:    Rot90,
+			kick:   0,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var g Game
+			g.Start(Config{Rotation: tc.rs})
+			boardFromString(&g.board, tc.board)
+			g.current = tc.piece
+			g.kick = -1
+			g.Step(tc.action, 0)
+			p := g.Current()
+			if got, want := p.Pos, tc.pos; got != want {
+				t.Errorf("pos: got %v; want %v", got, want)
+			}
+			if got, want := p.Rot, tc.rot; got != want {
+				t.Errorf("rotation: got %v; want %v", got, want)
+			}
+			if got, want := g.Kick(), tc.kick; got != want {
+				t.Errorf("kick: got %v; want %v", got, want)
+			}
+		})
+	}
+}
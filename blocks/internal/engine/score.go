@@ -0,0 +1,71 @@
+package engine
+
+import "time"
+
+// Score holds the score data of a game.
+type Score struct {
+	Total  int
+	Lines  int
+	Level  int
+	Clears [4]int // number of 1, 2, 3 and 4 or more lines clears
+	Pieces int    // number of locked pieces
+	Last   Clear  // outcome of the last locked piece
+	Combo  int    // number of consecutive line clears after the first one
+	B2B    int    // number of consecutive back-to-back difficult clears
+	Rule   Scoring
+	Time   time.Duration // elapsed game time
+
+	clears    int  // lines cleared since the last level change
+	streak    int  // number of consecutive line clears
+	difficult bool // whether or not the last line clear was difficult
+}
+
+// Gravity returns the duration between two moves down of the current piece at level l.
+//
+// https://tetris.wiki/Tetris_(NES,_Nintendo)
+func Gravity(l int) time.Duration {
+	g := 1
+	switch {
+	case l <= 8:
+		g = 48 - 5*l
+	case l == 9:
+		g = 6
+	case l <= 12:
+		g = 5
+	case l <= 15:
+		g = 4
+	case l <= 18:
+		g = 3
+	case l <= 28:
+		g = 2
+	}
+	// 30 frames ~ 1500ms
+	return time.Duration(g*1500/30) * time.Millisecond
+}
+
+// NewBlock scores the cells soft and hard dropped by the player for a new locked piece.
+func (s *Score) NewBlock(soft, hard int) {
+	s.Total += s.Rule.rule().drop(soft, hard)
+	s.Pieces++
+}
+
+// NewLines scores the clear of a locked piece, which may have no lines.
+func (s *Score) NewLines(c Clear) (newLevel bool) {
+	s.Total += s.Rule.rule().clear(s, c) * (s.Level + 1)
+	num := c.Lines
+	if num == 0 {
+		return false
+	}
+	s.Lines += num
+	s.Clears[min(num, len(s.Clears))-1]++
+	// Level change check.
+	clears := s.clears + num
+	startLevel := s.Level
+	if clears >= (startLevel*10)+10 || clears >= max(100, (startLevel*10)-50) {
+		s.Level++
+		s.clears = 0
+		return true
+	}
+	s.clears = clears
+	return false
+}
@@ -0,0 +1,99 @@
+package engine
+
+// Scoring defines how points are awarded.
+type Scoring uint8
+
+const (
+	ScoringNES       Scoring = iota // NES
+	ScoringGuideline                // Guideline
+	Scoring_
+)
+
+// scoreRule awards points, before applying the level multiplier.
+type scoreRule interface {
+	// drop returns the points for the cells soft and hard dropped by the player.
+	drop(soft, hard int) int
+	// clear returns the points for the clear of a locked piece,
+	// updating the combo and back-to-back state of the score.
+	clear(s *Score, c Clear) int
+}
+
+func (sc Scoring) rule() scoreRule {
+	switch sc {
+	case ScoringGuideline:
+		return guidelineRule{}
+	}
+	return nesRule{}
+}
+
+// nesRule is the original Nintendo scoring system, with the spins scored as per the guideline.
+//
+// https://tetris.wiki/Scoring#Original_Nintendo_scoring_system
+type nesRule struct{}
+
+func (nesRule) drop(soft, hard int) int {
+	return soft + hard
+}
+
+func (nesRule) clear(s *Score, c Clear) int {
+	points := [5]int{0, 40, 100, 300, 1200}[c.tier()]
+	switch {
+	case c.Spin == SpinFull && c.Piece == T:
+		points = max(points, [5]int{400, 800, 1200, 1600}[c.tier()])
+	case c.Spin != SpinNone:
+		points = max(points, [5]int{100, 200, 400}[c.tier()])
+	}
+	return points
+}
+
+// guidelineRule is the scoring system of the recent guideline compatible games.
+//
+// https://tetris.wiki/Scoring#Recent_guideline_compatible_games
+type guidelineRule struct{}
+
+func (guidelineRule) drop(soft, hard int) int {
+	return soft + 2*hard
+}
+
+func (guidelineRule) clear(s *Score, c Clear) int {
+	var points [5]int
+	switch {
+	case c.Spin == SpinFull && c.Piece == T:
+		points = [5]int{400, 800, 1200, 1600, 1600}
+	case c.Spin != SpinNone:
+		points = [5]int{100, 200, 400, 500, 800}
+	default:
+		points = [5]int{0, 100, 300, 500, 800}
+	}
+	p := points[c.tier()]
+	if c.Lines == 0 {
+		// The combo is broken by any piece not clearing lines.
+		s.Combo = 0
+		s.streak = 0
+		return p
+	}
+	// Tetrises and spins are difficult clears, which get a bonus if back-to-back.
+	switch difficult := c.tier() == 4 || c.Spin != SpinNone; {
+	case !difficult:
+		s.B2B = 0
+		s.difficult = false
+	case s.difficult:
+		s.B2B++
+		p += p / 2
+	default:
+		s.difficult = true
+	}
+	if s.streak > 0 {
+		s.Combo++
+		p += 50 * s.Combo
+	}
+	s.streak++
+	if c.Perfect {
+		pc := [5]int{0, 800, 1200, 1800, 2000}[c.tier()]
+		if c.tier() == 4 && s.B2B > 0 {
+			pc = 3200
+		}
+		p += pc
+	}
+	return p
+}
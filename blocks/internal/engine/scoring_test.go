@@ -0,0 +1,48 @@
+package engine
+
+import "testing"
+
+func TestScoringGuideline(t *testing.T) {
+	s := Score{Rule: ScoringGuideline}
+	for i, tc := range []struct {
+		clear      Clear
+		total      int
+		combo, b2b int
+	}{
+		{Clear{Piece: I, Lines: 4}, 800, 0, 0},
+		{Clear{Piece: T, Spin: SpinFull, Lines: 2}, 800 + 1800 + 50, 1, 1},
+		{Clear{Piece: L, Lines: 1}, 2650 + 100 + 100, 2, 0},
+		{Clear{Piece: T, Spin: SpinMini}, 2850 + 100, 0, 0},
+		{Clear{Piece: I, Lines: 4, Perfect: true}, 2950 + 800 + 2000, 0, 0},
+	} {
+		s.NewLines(tc.clear)
+		if got, want := s.Total, tc.total; got != want {
+			t.Errorf("%d: total: got %d; want %d", i, got, want)
+		}
+		if got, want := s.Combo, tc.combo; got != want {
+			t.Errorf("%d: combo: got %d; want %d", i, got, want)
+		}
+		if got, want := s.B2B, tc.b2b; got != want {
+			t.Errorf("%d: b2b: got %d; want %d", i, got, want)
+		}
+	}
+	if got, want := s.Level, 1; got != want {
+		t.Errorf("level: got %d; want %d", got, want)
+	}
+}
+
+func TestScoringDrop(t *testing.T) {
+	for _, tc := range []struct {
+		rule  Scoring
+		total int
+	}{
+		{ScoringNES, 8},
+		{ScoringGuideline, 13},
+	} {
+		s := Score{Rule: tc.rule}
+		s.NewBlock(3, 5)
+		if got, want := s.Total, tc.total; got != want {
+			t.Errorf("%v: got %d; want %d", tc.rule, got, want)
+		}
+	}
+}
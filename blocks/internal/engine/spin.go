@@ -0,0 +1,105 @@
+package engine
+
+import (
+	"image"
+	"strings"
+)
+
+// Spin is the kind of spin performed by a piece rotated into its locking position.
+type Spin uint8
+
+const (
+	SpinNone Spin = iota
+	SpinMini
+	SpinFull
+)
+
+// Clear describes the outcome of a locked piece.
+type Clear struct {
+	Piece   PieceID
+	Spin    Spin
+	Lines   int  // number of lines cleared
+	Perfect bool // whether or not the board is empty once the lines are cleared
+}
+
+// Notable reports whether or not the clear deserves to be shown to the player.
+func (c Clear) Notable() bool {
+	return c.Spin != SpinNone || c.tier() == 4 || c.Perfect
+}
+
+// tier returns the number of lines cleared, clears of more than 4 lines
+// by larger pieces being scored as 4 lines ones.
+func (c Clear) tier() int {
+	return min(c.Lines, 4)
+}
+
+func (c Clear) String() string {
+	names := [...]string{"", "SINGLE", "DOUBLE", "TRIPLE", "TETRIS"}
+	s := names[c.tier()]
+	if c.Spin != SpinNone {
+		s = strings.TrimSpace(c.Piece.String() + "-SPIN " + s)
+		if c.Spin == SpinMini && c.Piece == T {
+			s = "MINI " + s
+		}
+	}
+	if c.Perfect {
+		s += " PERFECT CLEAR"
+	}
+	return s
+}
+
+// spin returns the kind of spin of the current piece.
+//
+// The piece must have been rotated last. A T piece then spins if 3 of the
+// corners around its center are occupied, and fully if both corners
+// on its pointing side are or the last SRS kick was used.
+// Other pieces, but the O ones, spin if enabled and they cannot move
+// left, right or up, which counts as a mini spin.
+func (g *Game) spin() Spin {
+	p := &g.current
+	switch {
+	case g.kick < 0:
+		return SpinNone
+	case p.ID == T:
+		return g.tSpin()
+	case p.ID == O || p.ID == BigO || !g.config.AllSpin:
+		return SpinNone
+	case g.fits(-1, 0) || g.fits(1, 0) || g.fits(0, -1):
+		return SpinNone
+	}
+	return SpinMini
+}
+
+func (g *Game) tSpin() Spin {
+	p := &g.current
+	var cells [3][3]bool
+	p.Walk(func(x, y, _, _ int) bool {
+		cells[y][x] = true
+		return false
+	})
+	// The T points to the opposite of its empty side.
+	var front image.Point
+	for _, d := range []image.Point{{0, -1}, {1, 0}, {0, 1}, {-1, 0}} {
+		if !cells[1+d.Y][1+d.X] {
+			front = d.Mul(-1)
+		}
+	}
+	var corners, fronts int
+	for _, c := range []image.Point{{-1, -1}, {1, -1}, {-1, 1}, {1, 1}} {
+		x, y := p.Pos.X+1+c.X, p.Pos.Y+1+c.Y
+		if y >= 0 && g.board.Get(x, y) == Empty {
+			continue
+		}
+		corners++
+		if c.X == front.X || c.Y == front.Y {
+			fronts++
+		}
+	}
+	switch {
+	case corners < 3:
+		return SpinNone
+	case fronts == 2 || g.kick == len(kick{})-1:
+		return SpinFull
+	}
+	return SpinMini
+}
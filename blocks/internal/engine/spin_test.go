@@ -0,0 +1,73 @@
+package engine
+
+import (
+	"image"
+	"testing"
+)
+
+func TestSpin(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		board   string
+		piece   Piece
+		kick    int
+		allSpin bool
+		clear   Clear
+		total   int
+	}{
+		{
+			name:  "T-spin double",
+			board: `#..........# #..........# #...Z......# #Z...ZZZZZZ# #ZZ.ZZZZZZZ# ############`,
+			piece: Piece{Shape: Tetrominoes[T], Pos: image.Pt(2, 2)},
+			clear: Clear{Piece: T, Spin: SpinFull, Lines: 2},
+			total: 1200,
+		},
+		{
+			name:  "T moved after rotation",
+			board: `#..........# #..........# #...Z......# #Z...ZZZZZZ# #ZZ.ZZZZZZZ# ############`,
+			piece: Piece{Shape: Tetrominoes[T], Pos: image.Pt(2, 2)},
+			kick:  -1,
+			clear: Clear{Piece: T, Lines: 2},
+			total: 100,
+		},
+		{
+			name:  "mini T-spin single",
+			board: `#..........# #..........# #.Z.Z......# #Z...ZZZZZZ# #Z..ZZZZZZZ# ############`,
+			piece: Piece{Shape: Tetrominoes[T], Pos: image.Pt(2, 2)},
+			clear: Clear{Piece: T, Spin: SpinMini, Lines: 1},
+			total: 200,
+		},
+		{
+			name:    "S-spin single",
+			board:   `#..........# #..........# #XXX.......# #X..X......# #..XXXXXXXX# ############`,
+			piece:   Piece{Shape: Tetrominoes[S], Pos: image.Pt(1, 2)},
+			allSpin: true,
+			clear:   Clear{Piece: S, Spin: SpinMini, Lines: 1},
+			total:   200,
+		},
+		{
+			name:  "S-spin disabled",
+			board: `#..........# #..........# #XXX.......# #X..X......# #..XXXXXXXX# ############`,
+			piece: Piece{Shape: Tetrominoes[S], Pos: image.Pt(1, 2)},
+			clear: Clear{Piece: S, Lines: 1},
+			total: 40,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var g Game
+			g.Start(Config{AllSpin: tc.allSpin})
+			boardFromString(&g.board, tc.board)
+			g.current = tc.piece
+			g.kick = tc.kick
+
+			g.Step(DropHard, 0)
+			if got, want := g.Score().Last, tc.clear; got != want {
+				t.Fatalf("got %v; want %v", got, want)
+			}
+			g.Step(None, 0)
+			if got, want := g.Score().Total, tc.total; got != want {
+				t.Errorf("got %d; want %d", got, want)
+			}
+		})
+	}
+}
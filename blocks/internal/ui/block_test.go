@@ -1,10 +1,10 @@
 package ui
 
 import (
-	"fmt"
-	"image"
 	"strings"
 	"testing"
+
+	"github.com/pierrec/games/blocks/internal/engine"
 )
 
 func gridString(s string) string {
@@ -14,78 +14,52 @@ func gridString(s string) string {
 	) + "\n"
 }
 
-var textureMap = map[string]texture{}
-
-func init() {
-	for t := texture(0); t < _T; t++ {
-		textureMap[t.String()] = t
-	}
-}
-
-// Populate g with textures from data.
-func gridFromString(g *grid, data string) {
-	for y, row := range strings.Split(data, " ") {
-		for x, s := range row {
-			g.Set(x, y, textureMap[string(s)])
-		}
-	}
-}
-
-// Test laying then clearing blocks.
-// Only the non transparent block cells must be erased.
+// Test laying blocks.
+// Only the non transparent block cells must be drawn.
 func TestBlockLayout(t *testing.T) {
 	type tcase struct {
-		index blockID
+		index engine.PieceID
 		drawn string
 	}
 	for _, tc := range []tcase{
 		{
-			index: I,
-			drawn: `bbbb__ ______ ______`,
+			index: engine.I,
+			drawn: `______ bbbb__ ______`,
 		},
 		{
-			index: J,
-			drawn: `b_____ bbb___ ______`,
+			index: engine.J,
+			drawn: `______ bbb___ __b___`,
 		},
 		{
-			index: L,
-			drawn: `__b___ bbb___ ______`,
+			index: engine.L,
+			drawn: `______ bbb___ b_____`,
 		},
 		{
-			index: O,
+			index: engine.O,
 			drawn: `bb____ bb____ ______`,
 		},
 		{
-			index: S,
-			drawn: `_bb___ bb____ ______`,
+			index: engine.S,
+			drawn: `______ _bb___ bb____`,
 		},
 		{
-			index: T,
-			drawn: `_b____ bbb___ ______`,
+			index: engine.T,
+			drawn: `______ bbb___ _b____`,
 		},
 		{
-			index: Z,
-			drawn: `bb____ _bb___ ______`,
+			index: engine.Z,
+			drawn: `______ bb____ _bb___`,
 		},
 	} {
 		t.Run(tc.index.String(), func(t *testing.T) {
 			var g grid
 			g.Init(6, 3)
 			g.Fill(invisibleT)
-			var b block
-			b.Init(tc.index)
-			var got, want string
+			b := engine.Piece{Shape: engine.Tetrominoes[tc.index]}
 
-			b.layout(&g, false)
-			got = g.String()
-			want = gridString(tc.drawn)
-			if got != want {
-				t.Errorf("got %q; want %q", got, want)
-			}
-
-			b.layout(&g, true)
-			got = g.String()
-			want = strings.ReplaceAll(gridString(tc.drawn), blackT.String(), transparentT.String())
+			layoutBlock(&g, &b, blackT, nil)
+			got := g.String()
+			want := gridString(tc.drawn)
 			if got != want {
 				t.Errorf("got %q; want %q", got, want)
 			}
@@ -93,66 +67,49 @@ func TestBlockLayout(t *testing.T) {
 	}
 }
 
-func TestBlockCollision(t *testing.T) {
-	type coll struct {
-		left, right, down bool
+// Test that gradients follow the block rotation.
+func TestBlockTexture(t *testing.T) {
+	b := engine.Piece{Shape: engine.Tetrominoes[engine.I]}
+	for _, tc := range []struct {
+		rot  engine.Rotation
+		want texture
+	}{
+		{engine.Rot0, gradientNT | redT},
+		{engine.Rot90, gradientET | redT},
+		{engine.Rot180, gradientST | redT},
+		{engine.Rot270, gradientWT | redT},
+	} {
+		b.Rot = tc.rot
+		if got := blockTexture(&b, 0, 1, redT|gradientNT, nil); got != tc.want {
+			t.Errorf("%d: got %v; want %v", tc.rot, got, tc.want)
+		}
 	}
-	type tcase struct {
-		index blockID
-		pos   image.Point
-		grid  []string
-		res   []coll
+	if got, want := blockTexture(&b, 0, 1, redT|cornerT, nil), redT|cornerT; got != want {
+		t.Errorf("got %v; want %v", got, want)
 	}
-	for _, tc := range []tcase{
-		{
-			index: I,
-			pos:   image.Pt(1, 0),
-			grid: []string{
-				`TbbbbT TTTTTT`,
-				`_bbbb_ Ta_TTTT`,
-			},
-			res: []coll{
-				{false, false, false},
-				{true, true, true},
-			},
-		},
-	} {
-		t.Run(tc.index.String(), func(t *testing.T) {
-			for gi, gs := range tc.grid {
-				t.Run(fmt.Sprintf("%d", gi), func(t *testing.T) {
-					var g grid
-					g.Init(6, 3)
-					gridFromString(&g, gs)
-					var b block
-					b.Init(tc.index)
-					b.pos = tc.pos
-					b.layout(&g, false)
-					var got, want bool
+}
 
-					b.pos.X--
-					got = b.check(&g)
-					want = tc.res[gi].left
-					if got != want {
-						t.Errorf("left: got %v; want %v", got, want)
-					}
-					b.pos.X++
-					b.pos.X += b.Width()
-					got = b.check(&g)
-					want = tc.res[gi].right
-					if got != want {
-						t.Errorf("right: got %v; want %v", got, want)
-					}
-					b.pos.X -= b.Width()
+// Test that the ghost block keeps its outline whatever the texture.
+func TestBlockTextureGhost(t *testing.T) {
+	b := engine.Piece{Shape: engine.Tetrominoes[engine.I]}
+	for _, bt := range []texture{redT | uniformT, redT | cornerT, redT | gradientNT, redT | gradientSWT} {
+		got := blockTexture(&b, 0, 1, bt.ghost(), nil)
+		if got.pattern() != outlineT || got&blurT == 0 {
+			t.Errorf("%v: got %v; want %v", bt, got, redT|outlineT|blurT)
+		}
+	}
+}
 
-					b.pos.Y++
-					got = b.check(&g)
-					want = tc.res[gi].down
-					if got != want {
-						t.Errorf("down: got %v; want %v", got, want)
-					}
-					b.pos.Y--
-				})
-			}
-		})
+// Test the textures of the blocks of the other sets.
+func TestBlockTextureSets(t *testing.T) {
+	big := engine.Piece{Shape: engine.Big[engine.I]}
+	for _, pt := range [][2]int{{0, 2}, {1, 3}, {7, 2}} {
+		if got, want := blockTexture(&big, pt[0], pt[1], redT|gradientNT, nil), gradientNT|redT; got != want {
+			t.Errorf("%v: got %v; want %v", pt, got, want)
+		}
+	}
+	penta := engine.Piece{Shape: engine.Pentominoes[0]}
+	if got, want := blockTexture(&penta, 1, 0, redT|gradientNT, nil), uniformT|redT; got != want {
+		t.Errorf("got %v; want %v", got, want)
 	}
 }
@@ -1,69 +1,42 @@
-// +build !cha,!flo,!luc
-
 package ui
 
-var blocks = []block{
-	I: {
-		id: I,
-		data: [][]texture{
-			{transparentT, transparentT, transparentT, transparentT},
-			{gradientNT, gradientNT, gradientNT, gradientNT},
-			{transparentT, transparentT, transparentT, transparentT},
-			{transparentT, transparentT, transparentT, transparentT},
-		},
-		width: 4, height: 1,
+import "github.com/pierrec/games/blocks/internal/engine"
+
+// blocks defines the textures of the pieces cells.
+var blocks = [][][]texture{
+	engine.I: {
+		{transparentT, transparentT, transparentT, transparentT},
+		{gradientNT, gradientNT, gradientNT, gradientNT},
+		{transparentT, transparentT, transparentT, transparentT},
+		{transparentT, transparentT, transparentT, transparentT},
 	},
-	J: {
-		id: J,
-		data: [][]texture{
-			{transparentT, transparentT, transparentT},
-			{gradientNT, gradientNT, gradientNET},
-			{transparentT, transparentT, gradientET},
-		},
-		width: 3, height: 2,
+	engine.J: {
+		{transparentT, transparentT, transparentT},
+		{gradientNT, gradientNT, gradientNET},
+		{transparentT, transparentT, gradientET},
 	},
-	L: {
-		id: L,
-		data: [][]texture{
-			{transparentT, transparentT, transparentT},
-			{gradientNWT, gradientNT, gradientNT},
-			{gradientWT, transparentT, transparentT},
-		},
-		width: 3, height: 2,
+	engine.L: {
+		{transparentT, transparentT, transparentT},
+		{gradientNWT, gradientNT, gradientNT},
+		{gradientWT, transparentT, transparentT},
 	},
-	O: {
-		id: O,
-		data: [][]texture{
-			{gradientSET, gradientSWT},
-			{gradientNET, gradientNWT},
-		},
-		width: 2, height: 2,
+	engine.O: {
+		{gradientSET, gradientSWT},
+		{gradientNET, gradientNWT},
 	},
-	S: {
-		id: S,
-		data: [][]texture{
-			{transparentT, transparentT, transparentT},
-			{transparentT, uniformT, gradientNT},
-			{gradientST, uniformT, transparentT},
-		},
-		width: 3, height: 2,
+	engine.S: {
+		{transparentT, transparentT, transparentT},
+		{transparentT, uniformT, gradientNT},
+		{gradientST, uniformT, transparentT},
 	},
-	T: {
-		id: T,
-		data: [][]texture{
-			{transparentT, transparentT, transparentT},
-			{gradientNT, uniformT, gradientNT},
-			{transparentT, gradientNT, transparentT},
-		},
-		width: 3, height: 2,
+	engine.T: {
+		{transparentT, transparentT, transparentT},
+		{gradientNT, uniformT, gradientNT},
+		{transparentT, gradientNT, transparentT},
 	},
-	Z: {
-		id: Z,
-		data: [][]texture{
-			{transparentT, transparentT, transparentT},
-			{gradientNT, uniformT, transparentT},
-			{transparentT, uniformT, gradientST},
-		},
-		width: 3, height: 2,
+	engine.Z: {
+		{transparentT, transparentT, transparentT},
+		{gradientNT, uniformT, transparentT},
+		{transparentT, uniformT, gradientST},
 	},
 }
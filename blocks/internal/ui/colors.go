@@ -1,16 +1,16 @@
 package ui
 
-import "image/color"
+import "github.com/pierrec/games/blocks/internal/colors"
 
 var (
-	white = color.NRGBA{A: 255, R: 255, G: 255, B: 255}
-	black = color.NRGBA{A: 255}
+	white = colors.White
+	black = colors.Black
 	// Rainbow colors.
-	red    = color.NRGBA{A: 255, R: 255}
-	orange = color.NRGBA{A: 255, R: 255, G: 127}
-	yellow = color.NRGBA{A: 255, R: 255, G: 255}
-	green  = color.NRGBA{A: 255, G: 255}
-	blue   = color.NRGBA{A: 255, B: 255}
-	indigo = color.NRGBA{A: 255, R: 75, B: 130}
-	violet = color.NRGBA{A: 255, R: 238, G: 130, B: 238}
+	red    = colors.Red
+	orange = colors.Orange
+	yellow = colors.Yellow
+	green  = colors.Green
+	blue   = colors.Blue
+	indigo = colors.Indigo
+	violet = colors.Violet
 )
@@ -0,0 +1,219 @@
+package ui
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/pierrec/games/blocks/internal/engine"
+)
+
+// maxCustomBlocks is the maximum number of custom blocks.
+const maxCustomBlocks = 64
+
+// customColors defines the colors of the custom blocks cells, by block, row and column.
+type customColors [][][]texture
+
+// color returns the color of the custom block cell at (x, y) of its unrotated shape.
+func (cc customColors) color(p *engine.Piece, x, y int) (texture, bool) {
+	if p.ID < engine.Custom {
+		return 0, false
+	}
+	i := int(p.ID - engine.Custom)
+	// Replays may use blocks since changed.
+	if i >= len(cc) || y >= len(cc[i]) || x >= len(cc[i][y]) {
+		return 0, false
+	}
+	return cc[i][y][x], true
+}
+
+// loadCustomBlocks reads the custom blocks from the file in the data directory
+// and checks that they fit on the board.
+// Their colors are kept for the games displaying them.
+func (ui *UI) loadCustomBlocks(cols, rows, hidden int) (shapes []engine.Shape, err error) {
+	dir, err := dataDir()
+	if err != nil {
+		return nil, err
+	}
+	fName := filepath.Join(dir, ui.Blocks)
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("custom blocks in %s: %w", fName, err)
+		}
+	}()
+	f, err := os.Open(fName)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	shapes, colors, err := readCustomBlocks(f)
+	if err != nil {
+		return nil, err
+	}
+	for i, s := range shapes {
+		if err := s.Check(cols, rows, hidden); err != nil {
+			return nil, fmt.Errorf("block %d: %w", i+1, err)
+		}
+	}
+	ui.customBlocks = colors
+	return shapes, nil
+}
+
+// readCustomBlocks parses the blocks definitions, separated by empty lines.
+//
+// A block is defined by the rows of its cells, as displayed by grid.String:
+// _ or a space for an empty cell and a color letter for a filled one, in that color.
+// It can be followed by a "center x y" line setting its rotation center,
+// in cells from its top left one and on a cell or between 4 cells
+// with a .5 fraction, the middle of its cells by default,
+// and a "spawn x y" line offsetting its spawn position from the top middle of the board.
+//
+// Lines starting with # are ignored.
+func readCustomBlocks(r io.Reader) (shapes []engine.Shape, colors customColors, err error) {
+	var (
+		cells  []image.Point
+		cols   []texture
+		center *image.Point
+		offset image.Point
+		start  int // first line of the current block
+	)
+	add := func() error {
+		if cells == nil {
+			return nil
+		}
+		if len(shapes) == maxCustomBlocks {
+			return fmt.Errorf("more than %d blocks", maxCustomBlocks)
+		}
+		c := engine.Center(cells)
+		if center != nil {
+			c = *center
+		}
+		id := engine.Custom + engine.PieceID(len(shapes))
+		s, err := engine.NewShape(id, cells, c, offset)
+		if err != nil {
+			return fmt.Errorf("block at line %d: %w", start, err)
+		}
+		// Map the cells to the shape padded around its center.
+		var pad image.Point
+		p := engine.Piece{Shape: s}
+		p.Walk(func(x, y, _, _ int) bool {
+			pad = image.Pt(x, y).Sub(cells[0])
+			return true
+		})
+		n := len(s.Data)
+		col := make([][]texture, n)
+		for y := range col {
+			col[y] = make([]texture, n)
+		}
+		for i, c := range cells {
+			c = c.Add(pad)
+			col[c.Y][c.X] = cols[i]
+		}
+		shapes = append(shapes, s)
+		colors = append(colors, col)
+		cells, cols, center, offset = nil, nil, nil, image.Point{}
+		return nil
+	}
+
+	s := bufio.NewScanner(r)
+	var y int // row of the current block
+	for line := 1; s.Scan(); line++ {
+		// Leading spaces are part of the rows.
+		text := strings.TrimRightFunc(s.Text(), unicode.IsSpace)
+		switch fields := strings.Fields(text); {
+		case strings.HasPrefix(strings.TrimSpace(text), "#"):
+		case text == "":
+			if err := add(); err != nil {
+				return nil, nil, err
+			}
+		case fields[0] == "center" || fields[0] == "spawn":
+			if cells == nil {
+				return nil, nil, fmt.Errorf("line %d: %s without block", line, fields[0])
+			}
+			half := fields[0] == "center"
+			pt, err := parsePoint(fields[1:], half)
+			if err != nil {
+				return nil, nil, fmt.Errorf("line %d: %s: %w", line, fields[0], err)
+			}
+			if half {
+				center = &pt
+			} else {
+				offset = pt
+			}
+		default:
+			if cells == nil {
+				start, y = line, 0
+			}
+			for x, c := range []rune(text) {
+				if c == '_' || c == ' ' {
+					continue
+				}
+				t, ok := parseColor(c)
+				if !ok {
+					return nil, nil, fmt.Errorf("line %d: invalid cell %q", line, c)
+				}
+				cells = append(cells, image.Pt(x, y))
+				cols = append(cols, t)
+			}
+			if cells == nil {
+				// Keep track of the block, even if starting with empty rows.
+				cells = []image.Point{}
+			}
+			y++
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, nil, err
+	}
+	if err := add(); err != nil {
+		return nil, nil, err
+	}
+	if len(shapes) == 0 {
+		return nil, nil, errors.New("no blocks")
+	}
+	return shapes, colors, nil
+}
+
+// parseColor returns the color texture displayed as c.
+func parseColor(c rune) (texture, bool) {
+	start, end := textureColors()
+	for t := start; t <= end; t++ {
+		if t.String() == string(c) {
+			return t, true
+		}
+	}
+	return 0, false
+}
+
+// parsePoint parses the x and y coordinates, doubled if half cells are allowed.
+func parsePoint(fields []string, half bool) (image.Point, error) {
+	if len(fields) != 2 {
+		return image.Point{}, errors.New("expected x and y")
+	}
+	var v [2]int
+	for i, f := range fields {
+		if !half {
+			n, err := strconv.Atoi(f)
+			if err != nil {
+				return image.Point{}, err
+			}
+			v[i] = n
+			continue
+		}
+		n, err := strconv.ParseFloat(f, 64)
+		if err != nil {
+			return image.Point{}, err
+		}
+		if v[i] = int(2 * n); float64(v[i]) != 2*n {
+			return image.Point{}, fmt.Errorf("%s not a multiple of 0.5", f)
+		}
+	}
+	return image.Pt(v[0], v[1]), nil
+}
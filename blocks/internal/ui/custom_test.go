@@ -0,0 +1,74 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pierrec/games/blocks/internal/engine"
+)
+
+func TestReadCustomBlocks(t *testing.T) {
+	const blocks = `# Custom blocks.
+RR
+RR
+center 0.5 0.5
+
+_G_
+GBG
+spawn 0 1
+
+ Y
+YY
+`
+	shapes, colors, err := readCustomBlocks(strings.NewReader(blocks))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(shapes), 3; got != want {
+		t.Fatalf("got %d blocks; want %d", got, want)
+	}
+	if got, want := shapes[1].ID, engine.Custom+1; got != want {
+		t.Errorf("got ID %v; want %v", got, want)
+	}
+	if got, want := shapes[1].SpawnOffset.Y, 1; got != want {
+		t.Errorf("got spawn offset %d; want %d", got, want)
+	}
+
+	for i, want := range []string{
+		`RR____ RR____ ______`,
+		`_G____ GBG___ ______`,
+		`_Y____ YY____ ______`,
+	} {
+		var g grid
+		g.Init(6, 3)
+		g.Fill(invisibleT)
+		p := engine.Piece{Shape: shapes[i]}
+		layoutBlock(&g, &p, blackT, colors)
+		if got, want := g.String(), gridString(want); got != want {
+			t.Errorf("%d: got %q; want %q", i, got, want)
+		}
+	}
+}
+
+func TestReadCustomBlocksErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name, blocks, err string
+	}{
+		{"empty", "# nothing\n", "no blocks"},
+		{"cell", "RX\n", `line 1: invalid cell 'X'`},
+		{"connected", "R_R\n", "block at line 1: cells not connected"},
+		{"center", "RR\ncenter 0.5 0\n", "block at line 1: center (0.5, 0) not on a cell or between 4 cells"},
+		{"fraction", "RR\ncenter 0.3 0\n", "line 2: center: 0.3 not a multiple of 0.5"},
+		{"spawn", "spawn 1 0\n", "line 1: spawn without block"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, err := readCustomBlocks(strings.NewReader(tc.blocks))
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if got, want := err.Error(), tc.err; got != want {
+				t.Errorf("got %q; want %q", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package ui
+
+import (
+	"image"
+	"time"
+
+	"gioui.org/io/key"
+	"gioui.org/io/pointer"
+	"gioui.org/layout"
+	"gioui.org/op"
+
+	"github.com/pierrec/games/blocks/internal/bot"
+	"github.com/pierrec/games/blocks/internal/engine"
+)
+
+// Demo timings.
+const (
+	demoIdle = 30 * time.Second // idle time on the home screen before the demo starts
+	demoOver = 3 * time.Second  // time the end of a demo game is shown
+)
+
+// demo has the bot play games on its own while the home screen is idle,
+// until any key or pointer event.
+type demo struct {
+	Game    game      // game played by the bot
+	idle    time.Time // time of the last input on the home screen
+	clock   time.Time // time the game was last played up to
+	end     time.Time // time the game ended
+	next    time.Time // time of the next bot action
+	bot     bot.Bot
+	stopped bool
+}
+
+// Reset restarts the idle time.
+func (d *demo) Reset() {
+	d.idle = time.Time{}
+}
+
+// Idle reports whether or not the home screen has been idle long enough
+// for the demo to start.
+func (d *demo) Idle(now time.Time) bool {
+	return !d.idle.IsZero() && now.Sub(d.idle) >= demoIdle
+}
+
+// Watch records the input events on the home screen, letting them through.
+func (d *demo) Watch(gtx layout.Context) {
+	if d.idle.IsZero() {
+		d.idle = gtx.Now
+	}
+	if d.input(gtx) {
+		d.idle = gtx.Now
+	}
+	// Wake up to start the demo.
+	op.InvalidateOp{At: d.idle.Add(demoIdle)}.Add(gtx.Ops)
+}
+
+// input registers the input handlers over the whole area and
+// reports whether or not any key or pointer event was received.
+func (d *demo) input(gtx layout.Context) (ok bool) {
+	for _, ev := range gtx.Events(d) {
+		switch ev.(type) {
+		case key.Event, key.EditEvent, pointer.Event:
+			ok = true
+		}
+	}
+	defer op.Save(gtx.Ops).Load()
+	pointer.PassOp{Pass: true}.Add(gtx.Ops)
+	pointer.Rect(image.Rectangle{Max: gtx.Constraints.Max}).Add(gtx.Ops)
+	pointer.InputOp{
+		Tag:   d,
+		Types: pointer.Press | pointer.Move | pointer.Scroll,
+	}.Add(gtx.Ops)
+	key.InputOp{Tag: d}.Add(gtx.Ops)
+	key.FocusOp{Tag: d}.Add(gtx.Ops)
+	return ok
+}
+
+// Start starts a demo game with the same settings as the player ones.
+func (d *demo) Start() {
+	d.stopped = false
+	d.bot = bot.Bot{}
+	d.Game.area = grid{} // resized to the game board
+	d.Game.play.Paint = d.Game.paint
+	d.Game.play.Start(engine.Config{
+		Level:      d.Game.StartLevel,
+		Randomizer: d.Game.Randomizer,
+		Rotation:   d.Game.Rotation,
+		Previews:   d.Game.Previews,
+		AllSpin:    d.Game.AllSpin,
+		Scoring:    d.Game.Scoring,
+		Cols:       d.Game.Cols,
+		Rows:       d.Game.Rows,
+		Hidden:     d.Game.Hidden,
+		Pieces:     d.Game.Pieces,
+		Shapes:     d.Game.Shapes,
+	})
+	d.Game.resetScore()
+	d.clock = time.Now()
+	d.end = time.Time{}
+	d.next = d.clock
+}
+
+// Stopped reports whether or not the demo was interrupted.
+func (d *demo) Stopped() bool {
+	return d.stopped
+}
+
+func (d *demo) update(gtx layout.Context) {
+	now := gtx.Now
+	if s := d.Game.play.State(); s != engine.StateRunning && s != engine.StateClearing {
+		if d.end.IsZero() {
+			d.end = now
+		}
+		if now.Sub(d.end) >= demoOver {
+			d.Start()
+		}
+	} else {
+		a := engine.None
+		if !now.Before(d.next) {
+			a = d.bot.Action(&d.Game.play)
+			d.next = now.Add(botDelay)
+		}
+		d.Game.play.Step(a, now.Sub(d.clock))
+		d.Game.score.Update(d.Game.play.Score())
+	}
+	d.clock = now
+	// Keep playing.
+	op.InvalidateOp{At: now.Add(replayFrame)}.Add(gtx.Ops)
+}
+
+func (d *demo) Layout(gtx layout.Context) layout.Dimensions {
+	if d.input(gtx) {
+		d.stopped = true
+		op.InvalidateOp{}.Add(gtx.Ops)
+		return layout.Dimensions{}
+	}
+	d.update(gtx)
+	return layout.Flex{
+		Axis: layout.Vertical,
+	}.Layout(gtx,
+		layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+			d.Game.init(gtx)
+			d.Game.setGridCellSize(gtx)
+			return d.Game.layoutPlay(gtx, false)
+		}),
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return layout.Center.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+				return d.Game.Label.Layout(gtx, "DEMO - press any key")
+			})
+		}),
+	)
+}
@@ -18,6 +18,8 @@ import (
 	"gioui.org/widget"
 	"git.sr.ht/~pierrec/giox/widgetx"
 
+	"github.com/pierrec/games/blocks/internal/bot"
+	"github.com/pierrec/games/blocks/internal/engine"
 	"github.com/pierrec/games/blocks/internal/widgets"
 )
 
@@ -30,16 +32,22 @@ const (
 	gameLineAnim                   // LINEANIM
 	gamePaused                     // PAUSED
 	gameOver                       // GAME OVER
+	gameTimeUp                     // TIME UP
 	gameLeft                       // GAMELEFT
 )
 
 const (
 	gamePause = iota
 	gameContinue
+	gameAutoplay
+	gameHints
 	gameBack
 	game_
 )
 
+// botDelay is the time between two actions of the bot playing the game.
+const botDelay = 100 * time.Millisecond
+
 type lines struct {
 	// Lines contains the position of full lines in ascending order.
 	Lines []int
@@ -75,43 +83,78 @@ type game struct {
 	Padding      unit.Value
 	StartLevel   int
 	KeyMap       func(string) int
+	KeyName      func(int) string
 	BlockTexture texture
+	Randomizer   engine.Random
+	Rotation     engine.RotationSystem
+	Ghost        bool // show where the current block lands
+	DAS          time.Duration
+	ARR          time.Duration
+	SoftDrop     int
+	LockDelay    time.Duration
+	LockReset    engine.LockReset
+	Previews     int  // number of next blocks
+	AllSpin      bool // detect the spins of all blocks
+	Scoring      engine.Scoring
+	Mode         engine.Mode
+	TimeLimit    time.Duration   // Ultra mode duration
+	Garbage      int             // Cheese Race garbage lines
+	Messiness    float64         // probability of the garbage holes to change column
+	Cols         int             // board width
+	Rows         int             // board height
+	Hidden       int             // board rows above the visible ones
+	Pieces       engine.PieceSet // block set
+	Shapes       []engine.Shape  // custom blocks
+	Colors       customColors    // colors of the custom blocks cells
 
 	state    gameState
 	overlay  widgetx.Modal
 	ticker   *time.Ticker
 	paused   *time.Ticker
-	current  block
+	tide     *time.Timer // fires when the next garbage line rises
+	ebb      *time.Timer // tide timer while paused
+	clock    time.Time   // time the game was last played up to
+	play     engine.Game
 	area     grid
 	lines    lines
-	next     block
-	areaNext grid
+	areaNext [engine.MaxPreviews]grid
+	areaHold grid
 	score    score
+	repeat   autoRepeat
+	bot      bot.Player // player in control of the game, if any
+	botNext  time.Time  // time of the next bot action
+	botted   bool       // whether or not the bot played the game
+	hints    bool       // whether or not the placement hints are shown
+	hinted   bool       // whether or not hints were shown during the game
+	hint     hint
 }
 
-// drawGridBorder draws an invisible border around the grid
-// (except the top).
-func (ui *game) drawGridBorder() {
-	sz := ui.area.Size()
-	cols, rows := sz.X, sz.Y
-	t := invisibleT
-	for x := 0; x < cols; x++ {
-		ui.area.Set(x, rows-1, t) // bottom line
+// drawGrid draws the board and the current block onto the grid.
+func (ui *game) drawGrid() {
+	board := ui.play.Board()
+	sz := board.Size()
+	for y := 0; y < sz.Y; y++ {
+		for x := 0; x < sz.X; x++ {
+			ui.area.Set(x, y, cellTexture(board.Get(x, y)))
+		}
 	}
-	for y := 0; y < rows; y++ {
-		ui.area.Set(0, y, t)      // left line
-		ui.area.Set(cols-1, y, t) // right line
+	if p := ui.play.Ghost(); ui.Ghost && p != nil {
+		layoutBlock(&ui.area, p, ui.BlockTexture.ghost(), ui.Colors)
+	}
+	if p := ui.play.Current(); p != nil {
+		layoutBlock(&ui.area, p, ui.BlockTexture, ui.Colors)
 	}
 }
 
+// setGridCellSize sizes the square cells so that the board fits the height
+// and leaves at least a third of the width to the side panels.
 func (ui *game) setGridCellSize(gtx layout.Context) {
-	rows := ui.area.Size().Y - 1 - 1 // border + top hidden row
+	sz := ui.area.Size()
+	rows := sz.Y - 1 - ui.play.Board().Hidden() // border + top hidden rows
+	cols := sz.X - 2                            // walls
 	pad := gtx.Metric.Px(ui.Padding) * 2
-	size := image.Point{
-		X: (gtx.Constraints.Max.Y - pad) / rows,
-		Y: (gtx.Constraints.Max.Y - pad) / rows,
-	}
-	ui.area.SetCellSize(size)
+	side := min((gtx.Constraints.Max.Y-pad)/rows, (gtx.Constraints.Max.X*2/3-pad)/cols)
+	ui.area.SetCellSize(image.Pt(side, side))
 }
 
 // Start starts the game by initializing blocks and the ticker.
@@ -122,21 +165,77 @@ func (ui *game) Start() {
 	case gamePaused:
 		ui.unpause()
 		return
-	case gameOver, gameLeft:
-		ui.area.Clear()
-		ui.drawGridBorder()
 	}
+	ui.bot, ui.botted = nil, false
+	ui.hinted = ui.hints
+	ui.play.Paint = ui.paint
+	ui.play.Start(engine.Config{
+		Level:      ui.StartLevel,
+		Randomizer: ui.Randomizer,
+		Rotation:   ui.Rotation,
+		LockDelay:  ui.LockDelay,
+		LockReset:  ui.LockReset,
+		Previews:   ui.Previews,
+		AllSpin:    ui.AllSpin,
+		Scoring:    ui.Scoring,
+		Mode:       ui.Mode,
+		TimeLimit:  ui.TimeLimit,
+		Garbage:    ui.Garbage,
+		Messiness:  ui.Messiness,
+		Cols:       ui.Cols,
+		Rows:       ui.Rows,
+		Hidden:     ui.Hidden,
+		Pieces:     ui.Pieces,
+		Shapes:     ui.Shapes,
+	})
+	ui.begin()
+}
+
+// Resume restores the game played up to the end of its replay, paused.
+func (ui *game) Resume(r *engine.Replay, botted, hinted bool) {
+	ui.state = gameRunning
+	ui.bot, ui.botted = nil, botted
+	ui.hinted = hinted || ui.hints
+	ui.play.Paint = ui.paint
+	r.Play(&ui.play, len(r.Steps))
+	ui.begin()
+	ui.score.Update(ui.play.Score())
+	ui.Pause()
+}
+
+// begin sets up the game just started.
+func (ui *game) begin() {
+	ui.repeat = autoRepeat{
+		DAS:      ui.DAS,
+		ARR:      ui.ARR,
+		SoftDrop: ui.SoftDrop,
+	}
+	if ui.area.Size() != ui.play.Board().Size() {
+		ui.area = grid{} // resized to the new board
+	}
+	ui.resetScore()
+	ui.clock = time.Now()
+	ui.setGravity()
+	ui.setTide()
+}
+
+// resetScore resets the score panel for the game just started.
+func (ui *game) resetScore() {
 	ui.score = score{
 		Label:        ui.ScoreLabel,
 		Padding:      ui.Padding.Scale(2),
-		Level:        ui.StartLevel,
+		Level:        ui.play.Config().Level,
 		LineColor:    ui.Border,
 		LineHeight:   unit.Dp(1),
 		LineOverflow: unit.Dp(6),
+		AnimBg:       ui.Background,
 	}
-	ui.setGravity()
-	ui.current.InitRandom(ui.BlockTexture)
-	ui.next.InitRandom(ui.BlockTexture)
+	ui.score.TimeLimit, _ = ui.play.TimeLeft()
+}
+
+// paint returns the texture of the piece cell as a board cell.
+func (ui *game) paint(p *engine.Piece, x, y int) engine.Cell {
+	return engine.Cell(blockTexture(p, x, y, ui.BlockTexture, ui.Colors))
 }
 
 // Pause pauses the game, without stopping the ticker.
@@ -149,16 +248,24 @@ func (ui *game) Pause() {
 }
 
 func (ui *game) pause() {
+	ui.repeat.Reset()
 	ui.paused = ui.ticker
 	ui.ticker = nil
+	ui.ebb = ui.tide
+	ui.tide = nil
 }
 
 func (ui *game) unpause() {
 	ui.ticker = ui.paused
 	ui.paused = nil
+	ui.tide = ui.ebb
+	ui.ebb = nil
+	// Do not account for the time spent paused.
+	ui.clock = time.Now()
+	ui.setTide()
 }
 
-// Stop marks the game as over and clears the ticker.
+// Stop marks the game as over, or its time as up, and clears the ticker.
 func (ui *game) Stop() {
 	switch ui.state {
 	case gamePaused:
@@ -167,17 +274,47 @@ func (ui *game) Stop() {
 	case gameRunning:
 		ui.ticker.Stop()
 		ui.ticker = nil
+		if ui.tide != nil {
+			ui.tide.Stop()
+			ui.tide = nil
+		}
 	}
 	ui.state = gameOver
+	if ui.play.State() == engine.StateTimeUp {
+		ui.state = gameTimeUp
+	}
 }
 
 func (ui *game) Over() (score []scoreData, over bool) {
-	if ui.state == gameOver && ui.overlay.Changed() {
+	if (ui.state == gameOver || ui.state == gameTimeUp) && ui.overlay.Changed() {
 		return ui.score.Scores(), true
 	}
 	return nil, false
 }
 
+// ScoreKind returns the kind of the game for its score.
+func (ui *game) ScoreKind() scoreKind {
+	k := newScoreKind(ui.play.Config())
+	k.Hints = ui.hinted
+	return k
+}
+
+// Autoplay hands the control of the game to the bot, or back to the player if nil.
+func (ui *game) Autoplay(p bot.Player) {
+	ui.bot = p
+	ui.botted = ui.botted || p != nil
+}
+
+// Botted reports whether or not the bot played the game.
+func (ui *game) Botted() bool {
+	return ui.botted
+}
+
+// Done reports whether or not the game reached the goal of its mode.
+func (ui *game) Done() bool {
+	return ui.play.State() == engine.StateDone
+}
+
 func (ui *game) Tick() <-chan time.Time {
 	if ui.ticker != nil {
 		return ui.ticker.C
@@ -185,54 +322,64 @@ func (ui *game) Tick() <-chan time.Time {
 	return nil
 }
 
-// Update manages the game loop and is triggered when a new loop
-// is to be started (upon ticker firing or user movement blocked).
-// It tries to move the current block down and if unsuccessful,
-// use the next block as the current one and try again. If it fails,
-// then the game is over, at which point the ticker is stopped and cleared.
-func (ui *game) Update(softDrop int) {
-	if ui.state != gameRunning {
-		return
+// Tide returns the channel of the timer firing when the next garbage line rises.
+func (ui *game) Tide() <-chan time.Time {
+	if ui.tide != nil {
+		return ui.tide.C
 	}
-	if ui.current.MoveDown(&ui.area) {
-		// The current block successfully moved down.
-		return
+	return nil
+}
+
+// Update manages the game loop and is triggered when the ticker fires at now.
+// It moves the current block down, the game engine using the next block
+// as the current one if it could not.
+func (ui *game) Update(now time.Time) {
+	ui.advance(now)
+}
+
+// advance plays the game up to now, the elapsed time moving the current block down
+// or locking it.
+func (ui *game) advance(now time.Time) {
+	if dt := now.Sub(ui.clock); dt > 0 {
+		ui.clock = now
+		ui.step(engine.None, dt)
 	}
-	// The current block can no longer move.
-	ui.checkFullLines()
-	ui.score.NewBlock(softDrop)
-	// Use a new block.
-	ui.current = ui.next
-	ui.next.InitRandom(ui.BlockTexture)
-}
-
-func (ui *game) checkFullLines() {
-	// Detect full lines.
-	pos := ui.current.Pos()
-	lines := ui.lines.Lines[:0]
-	sz := ui.area.Size().Sub(image.Pt(1, 1)) // with left and bottom wall correction
-	xn := sz.X
-	_, h := ui.current.Dims()
-	yn := min(pos.Y+h, sz.Y)
-fullLoop:
-	for y := pos.Y; y < yn; y++ {
-		for x := 1; x < xn; x++ {
-			if ui.area.Get(x, y) == transparentT {
-				continue fullLoop
-			}
-		}
-		// Full line!
-		lines = append(lines, y)
+}
+
+// step forwards the action to the game engine and applies its outcome.
+// If the game is over, the ticker is stopped and cleared.
+func (ui *game) step(a engine.Action, dt time.Duration) {
+	if ui.state != gameRunning {
+		return
 	}
-	if len(lines) > 0 {
+	ui.play.Step(a, dt)
+	ui.score.Update(ui.play.Score())
+	switch ui.play.State() {
+	case engine.StateClearing:
 		ui.state = gameFullLines
-		ui.lines.Lines = lines
+		ui.lines.Lines = ui.play.Lines()
+	case engine.StateOver, engine.StateDone, engine.StateTimeUp:
+		ui.Stop()
+	case engine.StateRunning:
+		ui.setTide()
+	}
+}
+
+// setTide sets the tide timer to the next garbage line, if they are rising.
+func (ui *game) setTide() {
+	d, ok := ui.play.NextGarbage()
+	switch {
+	case !ok:
+	case ui.tide == nil:
+		ui.tide = time.NewTimer(d)
+	default:
+		ui.tide.Stop()
+		ui.tide.Reset(d)
 	}
 }
 
 func (ui *game) setGravity() {
-	level := ui.score.CurrentLevel()
-	d := gameGravity(level)
+	d := ui.play.Gravity()
 	if ui.ticker == nil {
 		ui.ticker = time.NewTicker(d)
 	} else {
@@ -254,16 +401,15 @@ func (ui *game) init(gtx layout.Context) {
 				key.NameEnter, key.NameReturn,
 				key.NameSpace},
 		}
-		// Grid with a border
-		// and the first line as hidden to allow rotation while on top.
-		cols, rows := 10+2, 20+1+1
-		ui.area.Init(cols, rows)
+		// Grid with the board border and hidden lines.
+		sz := ui.play.Board().Size()
+		ui.area.Init(sz.X, sz.Y)
 		ui.area.Background = ui.Background
-		ui.areaNext.Background = ui.Background
+		for i := range ui.areaNext {
+			ui.areaNext[i].Background = ui.Background
+		}
+		ui.areaHold.Background = ui.Background
 		ui.setGridCellSize(gtx)
-		ui.drawGridBorder()
-		ui.current.KeyMap = ui.KeyMap
-		ui.next.KeyMap = ui.KeyMap
 		ui.score.AnimBg = ui.Background
 	}
 }
@@ -281,9 +427,22 @@ func (ui *game) update(gtx layout.Context, evs []event.Event) {
 		for _, ev := range evs {
 			switch e := ev.(type) {
 			case key.Event:
-				if e.State == key.Release {
-					switch ui.KeyMap(e.Name) {
-					case pauseGame:
+				k := ui.KeyMap(e.Name)
+				if k < 0 {
+					continue
+				}
+				if ui.bot != nil && k != pauseGame {
+					continue
+				}
+				a := keyActions[k]
+				switch e.State {
+				case key.Press:
+					if k != pauseGame && ui.repeat.Press(e.Name, a, gtx.Now) {
+						ui.step(a, 0)
+					}
+				case key.Release:
+					ui.repeat.Release(e.Name, a, gtx.Now)
+					if k == pauseGame {
 						ui.Pause()
 					}
 				}
@@ -292,6 +451,20 @@ func (ui *game) update(gtx layout.Context, evs []event.Event) {
 			}
 		}
 		pointer.CursorNameOp{Name: ptr}.Add(gtx.Ops)
+		if ui.bot != nil && !gtx.Now.Before(ui.botNext) {
+			ui.botNext = gtx.Now.Add(botDelay)
+			ui.step(ui.bot.Action(&ui.play), 0)
+		}
+		// Repeat the actions of the keys held down.
+		next := ui.repeat.Repeat(gtx.Now, ui.play.Gravity(), func(a engine.Action) {
+			ui.step(a, 0)
+		})
+		if !next.IsZero() {
+			op.InvalidateOp{At: next}.Add(gtx.Ops)
+		}
+		ui.advance(gtx.Now)
+		// Keep the timer running.
+		op.InvalidateOp{At: gtx.Now.Add(time.Second / 30)}.Add(gtx.Ops)
 	case gameFullLines:
 		ui.state = gameLineAnim
 		ui.lines.Start(ui.area.CellSize().Y)
@@ -301,6 +474,17 @@ func (ui *game) update(gtx layout.Context, evs []event.Event) {
 		case gameContinue:
 			ui.Start()
 			op.InvalidateOp{}.Add(gtx.Ops)
+		case gameAutoplay:
+			if ui.bot == nil {
+				ui.Autoplay(new(bot.Bot))
+			} else {
+				ui.Autoplay(nil)
+			}
+			op.InvalidateOp{}.Add(gtx.Ops)
+		case gameHints:
+			ui.hints = !ui.hints
+			ui.hinted = ui.hinted || ui.hints
+			op.InvalidateOp{}.Add(gtx.Ops)
 		case gameBack:
 			ui.state = gameLeft
 			op.InvalidateOp{}.Add(gtx.Ops)
@@ -317,7 +501,7 @@ func (ui *game) Layout(gtx layout.Context) layout.Dimensions {
 
 	var showOverlay bool
 	switch ui.state {
-	case gamePaused, gameOver:
+	case gamePaused, gameOver, gameTimeUp:
 		showOverlay = true
 		ui.update(gtx, nil)
 	case gameFullLines, gameLineAnim:
@@ -334,9 +518,14 @@ func (ui *game) Layout(gtx layout.Context) layout.Dimensions {
 		evs := gtx.Queue.Events(ui)
 		gtx.Queue = queue(evs)
 		ui.update(gtx, evs)
-		// Display the current block.
-		ui.current.Layout(gtx, &ui.area, ui.Update, ui.Stop)
 	}
+	return ui.layoutPlay(gtx, showOverlay)
+}
+
+// layoutPlay lays out the board with the current block and the side panels,
+// with the overlay on top of the board if shown.
+func (ui *game) layoutPlay(gtx layout.Context, showOverlay bool) layout.Dimensions {
+	ui.drawGrid()
 
 	var gridDims layout.Dimensions
 	return layout.Center.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
@@ -344,10 +533,12 @@ func (ui *game) Layout(gtx layout.Context) layout.Dimensions {
 			Axis: layout.Horizontal,
 		}.Layout(gtx,
 			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
-				start := image.Pt(0, 1) // hide the first line
+				start := image.Pt(0, ui.play.Board().Hidden()) // hide the top lines
 				end := ui.area.Size()
 				area := ui.area.Slice(start, end)
 				gridDims = ui.layoutPanel(gtx, area.Layout)
+				ui.dimBlock(gtx)
+				ui.hintBlock(gtx)
 				ui.animate(gtx)
 				// Display the pause/game over overlays on top of the grid.
 				if !showOverlay {
@@ -365,15 +556,46 @@ func (ui *game) Layout(gtx layout.Context) layout.Dimensions {
 					layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
 						return ui.layoutPanel(gtx, ui.layoutScore)
 					}),
-					layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+					// Make room for the queue of next blocks.
+					layout.Flexed(1+float32(ui.play.Config().Previews-1)/5, func(gtx layout.Context) layout.Dimensions {
 						return ui.layoutPanel(gtx, ui.layoutNextBlock)
 					}),
+					layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+						return ui.layoutPanel(gtx, ui.layoutHold)
+					}),
 				)
 			}),
 		)
 	})
 }
 
+// dimBlock darkens the current block while it is on the ground,
+// until it locks once its lock delay expires.
+func (ui *game) dimBlock(gtx layout.Context) {
+	left, ok := ui.play.Locking()
+	if !ok || ui.state != gameRunning {
+		return
+	}
+	delay := ui.play.Config().LockDelay
+	bg := ui.Background
+	bg.A = uint8(192 * (delay - left) / delay)
+	cell := ui.area.CellSize()
+	pad := gtx.Metric.Px(ui.Padding)
+	p := ui.play.Current()
+	p.Walk(func(x, y, _, _ int) bool {
+		// The walls are not displayed, as well as the hidden lines.
+		x += p.Pos.X - 1
+		y += p.Pos.Y - ui.play.Board().Hidden()
+		if y >= 0 {
+			min := image.Pt(pad+x*cell.X, pad+y*cell.Y)
+			paint.FillShape(gtx.Ops, bg, clip.Rect{Min: min, Max: min.Add(cell)}.Op())
+		}
+		return false
+	})
+	// Keep playing to lock the block on time.
+	op.InvalidateOp{}.Add(gtx.Ops)
+}
+
 func (ui *game) animate(gtx layout.Context) {
 	if ui.state != gameLineAnim {
 		return
@@ -395,21 +617,13 @@ func (ui *game) animate(gtx layout.Context) {
 	}
 	ui.state = gameRunning
 	ui.unpause()
-	// Move down all non empty lines before start by end-start amount.
-	xn := ui.area.Size().X - 1 // left wall correction applied
-	for _, line := range ui.lines.Lines {
-		for y := line; y >= 0; y-- {
-			for x := 1; x < xn; x++ {
-				t := transparentT
-				if y > 0 {
-					t = ui.area.Get(x, y-1)
-				}
-				ui.area.Set(x, y, t)
-			}
-		}
-	}
-	// Update the score.
-	if ui.score.NewLines(num) {
+	// Remove the full lines and update the score.
+	levelUp := ui.play.ClearLines()
+	ui.score.Update(ui.play.Score())
+	switch s := ui.play.State(); {
+	case s == engine.StateOver, s == engine.StateDone, s == engine.StateTimeUp:
+		ui.Stop()
+	case levelUp:
 		// Level changed: increase the gravity.
 		ui.setGravity()
 	}
@@ -432,7 +646,7 @@ func (ui *game) layoutOverlay(gtx layout.Context) {
 
 	macro := op.Record(gtx.Ops)
 	n := game_
-	if ui.state == gameOver {
+	if ui.state == gameOver || ui.state == gameTimeUp {
 		n = 1
 	}
 	layout.Center.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
@@ -453,9 +667,12 @@ func (ui *game) layoutOverlay(gtx layout.Context) {
 				})
 			})
 			switch ui.state {
-			case gameOver:
+			case gameOver, gameTimeUp:
 				l.Font.Weight = text.Bold
 				txt = ui.state.String()
+				if s := ui.play.State(); s == engine.StateDone {
+					txt = s.String()
+				}
 				return noTitle
 			case gamePaused:
 				switch i {
@@ -465,6 +682,10 @@ func (ui *game) layoutOverlay(gtx layout.Context) {
 					return noTitle
 				case gameContinue:
 					txt = "Continue"
+				case gameAutoplay:
+					txt = "Autoplay: " + onOff(ui.bot != nil)
+				case gameHints:
+					txt = "Hints: " + onOff(ui.hints)
 				case gameBack:
 					txt = "Quit"
 				}
@@ -484,12 +705,62 @@ func (ui *game) layoutScore(gtx layout.Context) layout.Dimensions {
 	return layout.Center.Layout(gtx, ui.score.Layout)
 }
 
+// layoutNextBlock lays out the queue of next blocks from top to bottom,
+// the first one being larger.
 func (ui *game) layoutNextBlock(gtx layout.Context) layout.Dimensions {
-	b := &ui.next
-	g := &ui.areaNext
+	queue := ui.play.Queue()
+	children := make([]layout.FlexChild, len(queue))
+	for i := range queue {
+		b := &queue[i]
+		g := &ui.areaNext[i]
+		g.Resize(b.Dims())
+		g.Clear()
+		cell := blockCellSize(ui.area.CellSize(), b)
+		if i > 0 {
+			cell = cell.Div(2)
+		}
+		g.SetCellSize(cell)
+		layoutBlock(g, b, ui.BlockTexture, ui.Colors)
+		children[i] = layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			gtx.Constraints.Min.X = gtx.Constraints.Max.X
+			return layout.N.Layout(gtx, g.Layout)
+		})
+	}
+	gtx.Constraints.Min = gtx.Constraints.Max
+	return layout.Flex{
+		Axis:    layout.Vertical,
+		Spacing: layout.SpaceEvenly,
+	}.Layout(gtx, children...)
+}
+
+// layoutHold lays out the hold block, with the keys of the hint if shown.
+func (ui *game) layoutHold(gtx layout.Context) layout.Dimensions {
+	keys := ui.hintKeys()
+	if keys == "" {
+		return ui.layoutHoldBlock(gtx)
+	}
+	gtx.Constraints.Min = gtx.Constraints.Max
+	return layout.Flex{
+		Axis: layout.Vertical,
+	}.Layout(gtx,
+		layout.Flexed(1, ui.layoutHoldBlock),
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return layout.Center.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+				return ui.Label.Layout(gtx, keys)
+			})
+		}),
+	)
+}
+
+func (ui *game) layoutHoldBlock(gtx layout.Context) layout.Dimensions {
+	b := ui.play.Hold()
+	if b == nil {
+		return layout.Dimensions{Size: gtx.Constraints.Min}
+	}
+	g := &ui.areaHold
 	g.Resize(b.Dims())
 	g.Clear()
-	g.SetCellSize(ui.area.CellSize())
-	b.layout(g, false)
+	g.SetCellSize(blockCellSize(ui.area.CellSize(), b))
+	layoutBlock(g, b, ui.BlockTexture, ui.Colors)
 	return layout.Center.Layout(gtx, g.Layout)
 }
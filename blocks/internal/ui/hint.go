@@ -0,0 +1,111 @@
+package ui
+
+import (
+	"image"
+	"strings"
+
+	"gioui.org/layout"
+	"gioui.org/op/clip"
+	"gioui.org/op/paint"
+
+	"github.com/pierrec/games/blocks/internal/bot"
+	"github.com/pierrec/games/blocks/internal/engine"
+)
+
+// hint is the placement suggested by the bot for the current block.
+type hint struct {
+	adviser bot.Bot
+	pieces  int // locked blocks when suggested
+	id      engine.PieceID
+	canHold bool
+	target  *engine.Piece
+	hold    bool // whether or not the target is for the block obtained by holding
+}
+
+// update suggests a placement if the current block changed.
+func (h *hint) update(g *engine.Game) {
+	p := g.Current()
+	if p == nil {
+		h.target = nil
+		return
+	}
+	if h.target != nil && h.pieces == g.Score().Pieces && h.id == p.ID && h.canHold == g.CanHold() {
+		return
+	}
+	h.pieces, h.id, h.canHold = g.Score().Pieces, p.ID, g.CanHold()
+	h.target, h.hold = h.adviser.Suggest(g)
+}
+
+// showHint reports whether or not the hint is displayed.
+func (ui *game) showHint() bool {
+	if !ui.hints || ui.state != gameRunning {
+		return false
+	}
+	ui.hint.update(&ui.play)
+	return ui.hint.target != nil
+}
+
+// hintBlock outlines the suggested placement of the current block.
+func (ui *game) hintBlock(gtx layout.Context) {
+	if !ui.showHint() {
+		return
+	}
+	p := ui.hint.target
+	cells := make(map[image.Point]bool)
+	p.Walk(func(x, y, _, _ int) bool {
+		cells[p.Pos.Add(image.Pt(x, y))] = true
+		return false
+	})
+	c := ui.BlockTexture.nrgba()
+	c.A = 255
+	cell := ui.area.CellSize()
+	pad := gtx.Metric.Px(ui.Padding)
+	w := max(1, cell.X/8)
+	for pt := range cells {
+		// The walls are not displayed, as well as the hidden lines.
+		x, y := pt.X-1, pt.Y-ui.play.Board().Hidden()
+		if y < 0 {
+			continue
+		}
+		r := image.Rectangle{Min: image.Pt(pad+x*cell.X, pad+y*cell.Y)}
+		r.Max = r.Min.Add(cell)
+		for _, side := range [...]struct {
+			dx, dy int
+			edge   image.Rectangle
+		}{
+			{0, -1, image.Rect(r.Min.X, r.Min.Y, r.Max.X, r.Min.Y+w)},
+			{0, 1, image.Rect(r.Min.X, r.Max.Y-w, r.Max.X, r.Max.Y)},
+			{-1, 0, image.Rect(r.Min.X, r.Min.Y, r.Min.X+w, r.Max.Y)},
+			{1, 0, image.Rect(r.Max.X-w, r.Min.Y, r.Max.X, r.Max.Y)},
+		} {
+			if !cells[pt.Add(image.Pt(side.dx, side.dy))] {
+				paint.FillShape(gtx.Ops, c, clip.Rect(side.edge).Op())
+			}
+		}
+	}
+}
+
+// hintKeys returns the keys to press to lock the current block at the suggested placement.
+func (ui *game) hintKeys() string {
+	if !ui.showHint() {
+		return ""
+	}
+	if ui.hint.hold {
+		return ui.actionKey(engine.Hold)
+	}
+	var keys []string
+	for _, a := range bot.Moves(ui.play.Current(), ui.hint.target) {
+		keys = append(keys, ui.actionKey(a))
+	}
+	return strings.Join(keys, " ")
+}
+
+// actionKey returns the key bound to the action.
+func (ui *game) actionKey(a engine.Action) string {
+	for k, ka := range keyActions {
+		if ka == a && k != pauseGame {
+			return ui.KeyName(k)
+		}
+	}
+	return a.String()
+}
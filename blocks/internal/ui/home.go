@@ -1,6 +1,7 @@
 package ui
 
 import (
+	"image"
 	"strconv"
 	"time"
 
@@ -12,26 +13,34 @@ import (
 	"gioui.org/widget"
 	"git.sr.ht/~pierrec/giox/layoutx"
 
+	"github.com/pierrec/games/blocks/internal/engine"
 	"github.com/pierrec/games/blocks/internal/version"
 	"github.com/pierrec/games/blocks/internal/widgets"
 )
 
 type home struct {
-	Menu     widgets.Menu
-	Title    title
-	Version  widgets.Label
-	Error    error
-	levels   [10]widget.Bool
-	list     layoutx.ListWrap
-	selected int
-	errAnim  widgets.Anim
+	Menu      widgets.Menu
+	Title     title
+	Version   widgets.Label
+	Error     error
+	Resumable bool // whether or not there is a saved game to resume
+	levels    [10]widget.Bool
+	list      layoutx.ListWrap
+	selected  int
+	modes     [engine.Mode_]widget.Bool
+	listM     layoutx.ListWrap
+	mode      engine.Mode
+	errAnim   widgets.Anim
 }
 
 const (
-	homeLevels = iota
+	homeModes = iota
+	homeLevels
 	homeSpace1
 	homeStartGame
+	homeResumeGame
 	homeScoreBoard
+	homeReplays
 	homeSettings
 	homeSpace2
 	homeQuitGame
@@ -42,14 +51,23 @@ func (h *home) Level() int {
 	return h.selected
 }
 
+// Mode returns the selected game mode.
+func (h *home) Mode() engine.Mode {
+	return h.mode
+}
+
 func (h *home) saveConfig(cfg *config) {
 	cfg.Level = h.Level()
+	cfg.Mode = h.Mode()
 }
 
 func (h *home) loadConfig(cfg *config) {
 	if cfg.Level < len(h.levels) {
 		h.selected = cfg.Level
 	}
+	if cfg.Mode < engine.Mode_ {
+		h.mode = cfg.Mode
+	}
 }
 
 func (h *home) update() {
@@ -59,6 +77,14 @@ func (h *home) update() {
 		}
 		h.errAnim.Start(0, 1)
 	}
+	for i := range h.modes {
+		b := &h.modes[i]
+		if b.Changed() && b.Value {
+			h.modes[h.mode].Value = false
+			h.mode = engine.Mode(i)
+			return
+		}
+	}
 	for i := range h.levels {
 		b := &h.levels[i]
 		if b.Changed() && b.Value {
@@ -101,6 +127,8 @@ func (h *home) Layout(gtx layout.Context) layout.Dimensions {
 					func(gtx layout.Context, i int) widgets.MenuItem {
 						l := h.Menu.Label
 						switch i {
+						case homeModes:
+							return widgets.MenuTitle(h.layoutModes, "Select Mode")
 						case homeLevels:
 							return widgets.MenuTitle(h.layoutLevels, "Select Level")
 						case homeSpace1:
@@ -109,10 +137,21 @@ func (h *home) Layout(gtx layout.Context) layout.Dimensions {
 							return widgets.MenuButton(func(gtx layout.Context) layout.Dimensions {
 								return l.Layout(gtx, "Start Game")
 							})
+						case homeResumeGame:
+							if !h.Resumable {
+								return widgets.MenuSpacer(unit.Value{})
+							}
+							return widgets.MenuButton(func(gtx layout.Context) layout.Dimensions {
+								return l.Layout(gtx, "Resume Game")
+							})
 						case homeScoreBoard:
 							return widgets.MenuButton(func(gtx layout.Context) layout.Dimensions {
 								return l.Layout(gtx, "Score Board")
 							})
+						case homeReplays:
+							return widgets.MenuButton(func(gtx layout.Context) layout.Dimensions {
+								return l.Layout(gtx, "Replays")
+							})
 						case homeSettings:
 							return widgets.MenuButton(func(gtx layout.Context) layout.Dimensions {
 								return l.Layout(gtx, "Settings")
@@ -132,6 +171,40 @@ func (h *home) Layout(gtx layout.Context) layout.Dimensions {
 	)
 }
 
+func (h *home) layoutModes(gtx layout.Context) layout.Dimensions {
+	return layout.Center.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return h.listM.Layout(gtx, len(h.modes), func(gtx layout.Context, idx int) layout.Dimensions {
+			bg := h.Menu.Border.Color
+			l := h.Menu.Label
+			selected := engine.Mode(idx) == h.mode
+			if selected {
+				l.Font.Weight = text.Bold
+				l.Color, bg = bg, l.Color
+			}
+			return layout.Stack{}.Layout(gtx,
+				layout.Expanded(func(gtx layout.Context) layout.Dimensions {
+					size := gtx.Constraints.Min
+					if selected {
+						r := float32(size.Y) / 2
+						op := clip.UniformRRect(layout.FRect(image.Rectangle{Max: size}), r).Op(gtx.Ops)
+						paint.FillShape(gtx.Ops, bg, op)
+					}
+					return layout.Dimensions{Size: size}
+				}),
+				layout.Stacked(func(gtx layout.Context) layout.Dimensions {
+					return layout.Inset{
+						Left:  unit.Dp(8),
+						Right: unit.Dp(8),
+					}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+						return l.Layout(gtx, engine.Mode(idx).String())
+					})
+				}),
+				layout.Expanded(h.modes[idx].Layout),
+			)
+		}, nil)
+	})
+}
+
 func (h *home) layoutLevels(gtx layout.Context) layout.Dimensions {
 	return layout.Center.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
 		return h.list.Layout(gtx, len(h.levels), func(gtx layout.Context, idx int) layout.Dimensions {
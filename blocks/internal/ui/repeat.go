@@ -0,0 +1,108 @@
+package ui
+
+import (
+	"time"
+
+	"github.com/pierrec/games/blocks/internal/engine"
+)
+
+// autoRepeat repeats the move and soft drop actions while their key is held down,
+// independently of the OS key repeat.
+type autoRepeat struct {
+	DAS      time.Duration // Delayed Auto Shift: delay before the first move repeat
+	ARR      time.Duration // Auto Repeat Rate: delay between move repeats, 0 moving to the wall
+	SoftDrop int           // soft drop speed as a factor of the gravity
+
+	pressed map[string]engine.Action // actions of the keys held down
+	shift   engine.Action            // move being repeated
+	shiftAt time.Time                // time of the next move
+	drop    bool                     // whether or not the soft drop is repeated
+	dropAt  time.Time                // time of the next soft drop
+}
+
+// Reset stops all repeats.
+func (r *autoRepeat) Reset() {
+	r.pressed = nil
+	r.shift = engine.None
+	r.drop = false
+}
+
+// Press records the key of the action as pressed at now and
+// reports whether or not the action is to be applied,
+// repeated presses being reported by the OS while the key is held down.
+func (r *autoRepeat) Press(name string, a engine.Action, now time.Time) bool {
+	if _, ok := r.pressed[name]; ok {
+		return false
+	}
+	if r.pressed == nil {
+		r.pressed = make(map[string]engine.Action)
+	}
+	r.pressed[name] = a
+	switch a {
+	case engine.MoveLeft, engine.MoveRight:
+		r.shift = a
+		r.shiftAt = now.Add(r.DAS)
+	case engine.DropSoft:
+		r.drop = true
+		r.dropAt = time.Time{}
+	}
+	return true
+}
+
+// Release records the key of the action as released at now.
+// Releasing a move while the opposite one is still held down
+// repeats the latter again after its delay.
+func (r *autoRepeat) Release(name string, a engine.Action, now time.Time) {
+	delete(r.pressed, name)
+	switch {
+	case a == r.shift:
+		r.shift = engine.None
+		for _, b := range r.pressed {
+			if b == engine.MoveLeft || b == engine.MoveRight {
+				r.shift = b
+				r.shiftAt = now.Add(r.DAS)
+				break
+			}
+		}
+	case a == engine.DropSoft:
+		r.drop = false
+	}
+}
+
+// Repeat calls fn with every action repeat due at now and returns the time
+// of the next one, if any.
+func (r *autoRepeat) Repeat(now time.Time, gravity time.Duration, fn func(engine.Action)) (next time.Time) {
+	switch {
+	case r.shift == engine.None:
+	case r.ARR == 0:
+		// Move to the wall once the delay expired.
+		if r.shiftAt.After(now) {
+			next = r.shiftAt
+			break
+		}
+		for i := 0; i < engine.MaxCols; i++ {
+			fn(r.shift)
+		}
+	default:
+		for i := 0; !r.shiftAt.After(now) && i < engine.MaxCols; i++ {
+			fn(r.shift)
+			r.shiftAt = r.shiftAt.Add(r.ARR)
+		}
+		next = r.shiftAt
+	}
+	if r.drop {
+		d := gravity / time.Duration(max(1, r.SoftDrop))
+		if r.dropAt.IsZero() {
+			// The first drop was applied on key press.
+			r.dropAt = now.Add(d)
+		}
+		for i := 0; !r.dropAt.After(now) && i < engine.MaxRows; i++ {
+			fn(engine.DropSoft)
+			r.dropAt = r.dropAt.Add(d)
+		}
+		if next.IsZero() || r.dropAt.Before(next) {
+			next = r.dropAt
+		}
+	}
+	return
+}
@@ -0,0 +1,82 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pierrec/games/blocks/internal/engine"
+)
+
+func TestAutoRepeat(t *testing.T) {
+	const ms = time.Millisecond
+	r := autoRepeat{DAS: 100 * ms, ARR: 20 * ms, SoftDrop: 10}
+	t0 := time.Now()
+	var got []engine.Action
+	repeat := func(d time.Duration) time.Time {
+		got = got[:0]
+		return r.Repeat(t0.Add(d), 500*ms, func(a engine.Action) {
+			got = append(got, a)
+		})
+	}
+
+	if !r.Press("Left", engine.MoveLeft, t0) {
+		t.Fatal("key press not applied")
+	}
+	if r.Press("Left", engine.MoveLeft, t0.Add(30*ms)) {
+		t.Fatal("OS key repeat applied")
+	}
+	if next := repeat(99 * ms); len(got) != 0 || !next.Equal(t0.Add(100*ms)) {
+		t.Fatalf("got %v, next at %v", got, next.Sub(t0))
+	}
+	if next := repeat(100 * ms); len(got) != 1 || !next.Equal(t0.Add(120*ms)) {
+		t.Fatalf("got %v, next at %v", got, next.Sub(t0))
+	}
+	if repeat(145 * ms); len(got) != 2 {
+		t.Fatalf("got %v", got)
+	}
+
+	// Soft drop at gravity/10.
+	r.Press("Down", engine.DropSoft, t0.Add(145*ms))
+	if repeat(145 * ms); len(got) != 0 {
+		t.Fatalf("got %v", got)
+	}
+	r.Release("Left", engine.MoveLeft, t0.Add(145*ms))
+	if repeat(195 * ms); len(got) != 1 || got[0] != engine.DropSoft {
+		t.Fatalf("got %v", got)
+	}
+	r.Release("Down", engine.DropSoft, t0.Add(195*ms))
+	if next := repeat(400 * ms); len(got) != 0 || !next.IsZero() {
+		t.Fatalf("got %v, next at %v", got, next.Sub(t0))
+	}
+}
+
+func TestAutoRepeatOpposite(t *testing.T) {
+	const ms = time.Millisecond
+	r := autoRepeat{DAS: 100 * ms, ARR: 20 * ms}
+	t0 := time.Now()
+	var got []engine.Action
+	repeat := func(d time.Duration) time.Time {
+		got = got[:0]
+		return r.Repeat(t0.Add(d), 500*ms, func(a engine.Action) {
+			got = append(got, a)
+		})
+	}
+
+	r.Press("Left", engine.MoveLeft, t0)
+	r.Press("Right", engine.MoveRight, t0.Add(50*ms))
+	if repeat(150 * ms); len(got) != 1 || got[0] != engine.MoveRight {
+		t.Fatalf("got %v", got)
+	}
+	// The left key still held down repeats after its delay.
+	r.Release("Right", engine.MoveRight, t0.Add(160*ms))
+	if next := repeat(259 * ms); len(got) != 0 || !next.Equal(t0.Add(260*ms)) {
+		t.Fatalf("got %v, next at %v", got, next.Sub(t0))
+	}
+	if repeat(280 * ms); len(got) != 2 || got[0] != engine.MoveLeft {
+		t.Fatalf("got %v", got)
+	}
+	r.Release("Left", engine.MoveLeft, t0.Add(280*ms))
+	if next := repeat(400 * ms); len(got) != 0 || !next.IsZero() {
+		t.Fatalf("got %v, next at %v", got, next.Sub(t0))
+	}
+}
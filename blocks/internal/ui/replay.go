@@ -0,0 +1,55 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pierrec/games/blocks/internal/engine"
+)
+
+// Replay files are saved next to the config file, named after the time the game ended,
+// to the millisecond so that games ended within the same second get their own files.
+// The time is parsed without its fraction, optional in the names of older files.
+const (
+	replayPrefix = "blocks-"
+	replayExt    = ".replay"
+	replayDate   = "20060102-150405"
+	replayTime   = replayDate + ".000"
+)
+
+// saveReplay writes the replay of the last game.
+func (ui *UI) saveReplay() error {
+	return writeReplay(ui.game.play.Replay())
+}
+
+// writeReplay writes the replay in a new file.
+func writeReplay(r *engine.Replay) (err error) {
+	if len(r.Steps) == 0 {
+		return nil
+	}
+	dir, err := dataDir()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("replay in %s: %w", dir, err)
+		}
+	}()
+	fName := filepath.Join(dir, replayPrefix+time.Now().Format(replayTime)+replayExt)
+	// Never overwrite a replay.
+	f, err := os.OpenFile(fName, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0666)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		er := f.Close()
+		if err == nil {
+			err = er
+		}
+	}()
+	_, err = r.WriteTo(f)
+	return
+}
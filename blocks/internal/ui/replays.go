@@ -0,0 +1,451 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gioui.org/layout"
+	"gioui.org/op"
+	"gioui.org/unit"
+
+	"github.com/pierrec/games/blocks/internal/engine"
+	"github.com/pierrec/games/blocks/internal/widgets"
+)
+
+// replaysPage is the number of replays listed per page.
+const replaysPage = 10
+
+// replays lists the recorded games and plays them back.
+type replays struct {
+	Menu    widgets.Menu
+	Padding unit.Value
+	Game    game // game displaying the replay
+	entries []replayEntry
+	page    int // index of the page listed
+	table   widgets.Table
+	// Scores computed in the background, as playing the replays takes time.
+	scores  chan replayScore
+	pending int           // number of scores not received yet
+	stop    chan struct{} // closed to stop computing the scores
+	// Viewer.
+	viewing  bool
+	playback engine.Playback
+	paused   bool
+	speed    int       // index in replaySpeeds
+	clock    time.Time // time the replay was last played up to
+	controls widgets.Menu
+}
+
+type replayEntry struct {
+	date   time.Time
+	kind   scoreKind
+	value  string // ranking value of the game, empty until computed
+	replay *engine.Replay
+}
+
+// replayScore is the ranking value of the replay entry at index.
+type replayScore struct {
+	index int
+	value string
+}
+
+// Playback speed factors.
+var replaySpeeds = [...]float64{0.25, 0.5, 1, 2, 4}
+
+const defaultReplaySpeed = 2 // x1
+
+// Replay seek steps.
+const (
+	replayFrame = time.Second / 30
+	replaySkip  = 5 * time.Second
+)
+
+// Menu indexes.
+const (
+	replaysList = iota
+	replaysNewer
+	replaysOlder
+	replaysSpace
+	replaysBack
+	replays_
+)
+
+// Viewer controls indexes.
+const (
+	viewerBack = iota
+	viewerRestart
+	viewerRewind
+	viewerPlay
+	viewerStep
+	viewerForward
+	viewerSpeed
+	viewerTime
+	viewer_
+)
+
+// Load reads the replays, most recent first, and starts computing their scores
+// in the background. Invalid ones are skipped, the first error being returned.
+func (r *replays) Load() (err error) {
+	r.Stop()
+	r.entries = nil
+	r.page = 0
+	r.viewing = false
+	dir, err := dataDir()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("replays in %s: %w", dir, err)
+		}
+	}()
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		name := f.Name()
+		if f.IsDir() || !strings.HasPrefix(name, replayPrefix) || !strings.HasSuffix(name, replayExt) {
+			continue
+		}
+		e, er := loadReplay(filepath.Join(dir, name))
+		if er != nil {
+			if err == nil {
+				err = fmt.Errorf("%s: %w", name, er)
+			}
+			continue
+		}
+		r.entries = append(r.entries, e)
+	}
+	sort.Slice(r.entries, func(i, j int) bool {
+		return r.entries[i].date.After(r.entries[j].date)
+	})
+	r.score()
+	return
+}
+
+// score computes the scores of the entries in the background, in order.
+func (r *replays) score() {
+	list := make([]*engine.Replay, len(r.entries))
+	for i := range r.entries {
+		list[i] = r.entries[i].replay
+	}
+	scores := make(chan replayScore, len(list))
+	stop := make(chan struct{})
+	r.scores, r.stop, r.pending = scores, stop, len(list)
+	go func() {
+		for i, rp := range list {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			scores <- replayScore{index: i, value: replayValue(rp)}
+		}
+	}()
+}
+
+// Stop stops computing the scores of the replays.
+func (r *replays) Stop() {
+	if r.stop != nil {
+		close(r.stop)
+		r.stop = nil
+	}
+}
+
+// receiveScores sets the scores computed so far.
+func (r *replays) receiveScores() {
+	for r.pending > 0 {
+		select {
+		case s := <-r.scores:
+			r.entries[s.index].value = s.value
+			r.pending--
+		default:
+			return
+		}
+	}
+}
+
+// listed returns the entries of the page listed.
+func (r *replays) listed() []replayEntry {
+	start := r.page * replaysPage
+	return r.entries[start:min(start+replaysPage, len(r.entries))]
+}
+
+// pages returns the number of pages of entries.
+func (r *replays) pages() int {
+	return max(1, (len(r.entries)+replaysPage-1)/replaysPage)
+}
+
+// turn lists the page of older entries, or the newer one.
+func (r *replays) turn(older bool) {
+	if older {
+		r.page = min(r.page+1, r.pages()-1)
+	} else {
+		r.page = max(0, r.page-1)
+	}
+}
+
+// replayFileDate returns the date of the replay file named name.
+func replayFileDate(name string) (time.Time, error) {
+	ts := strings.TrimSuffix(strings.TrimPrefix(name, replayPrefix), replayExt)
+	return time.ParseInLocation(replayDate, ts, time.Local)
+}
+
+// loadReplay reads the replay in the file.
+func loadReplay(fName string) (e replayEntry, err error) {
+	e.date, err = replayFileDate(filepath.Base(fName))
+	if err != nil {
+		return
+	}
+	f, err := os.Open(fName)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	e.replay = new(engine.Replay)
+	if _, err = e.replay.ReadFrom(f); err != nil {
+		return
+	}
+	e.kind = newScoreKind(e.replay.Config)
+	return
+}
+
+// replayValue plays the replay to get the ranking value of its game.
+func replayValue(r *engine.Replay) string {
+	var g engine.Game
+	r.Play(&g, len(r.Steps))
+	var sc score
+	sc.Update(g.Score())
+	score := newScoreEntry(sc.Scores())
+	return rankValue(newScoreKind(r.Config).Mode, &score)
+}
+
+// view starts playing back the replay at i.
+func (r *replays) view(i int) {
+	r.viewing = true
+	r.paused = false
+	r.speed = defaultReplaySpeed
+	r.Game.area = grid{} // resized to the replay board
+	r.Game.play.Paint = r.Game.paint
+	r.playback = engine.Playback{
+		Replay: r.entries[i].replay,
+		Game:   &r.Game.play,
+	}
+	r.restart()
+}
+
+// restart plays the replay back from its start.
+func (r *replays) restart() {
+	r.playback.Start()
+	r.Game.resetScore()
+	r.clock = time.Now()
+}
+
+// seek moves the playback to t, within the replay.
+func (r *replays) seek(t time.Duration) {
+	if end := r.playback.Replay.Time(); t > end {
+		t = end
+	}
+	if t < 0 {
+		t = 0
+	}
+	if t < r.playback.Time() {
+		r.restart()
+	}
+	r.playback.Seek(t)
+}
+
+func (r *replays) init() {
+	if r.controls.Label.Shaper == nil {
+		r.controls = r.Menu
+		r.controls.List.Axis = layout.Horizontal
+		r.table = widgets.Table{
+			Hover:      r.Menu.Border.Color,
+			LineHeight: r.Menu.Border.Width,
+			LineColor:  r.Menu.Border.Color,
+		}
+	}
+}
+
+func (r *replays) update(gtx layout.Context) {
+	if !r.viewing {
+		r.receiveScores()
+		if r.pending > 0 {
+			// Keep receiving the scores.
+			op.InvalidateOp{At: gtx.Now.Add(replayFrame)}.Add(gtx.Ops)
+		}
+		if i := r.table.Clicked(); i >= 0 && i < len(r.listed()) {
+			r.view(r.page*replaysPage + i)
+		}
+		return
+	}
+	switch r.controls.Clicked() {
+	case viewerBack:
+		r.viewing = false
+		return
+	case viewerRestart:
+		r.restart()
+	case viewerRewind:
+		r.seek(r.playback.Time() - replaySkip)
+	case viewerPlay:
+		r.paused = !r.paused
+	case viewerStep:
+		r.paused = true
+		r.playback.Advance(replayFrame)
+	case viewerForward:
+		r.seek(r.playback.Time() + replaySkip)
+	case viewerSpeed:
+		r.speed = (r.speed + 1) % len(replaySpeeds)
+	}
+	now := gtx.Now
+	if !r.paused && !r.playback.Done() {
+		dt := float64(now.Sub(r.clock)) * replaySpeeds[r.speed]
+		r.playback.Advance(time.Duration(dt))
+		// Keep playing.
+		op.InvalidateOp{At: now.Add(replayFrame)}.Add(gtx.Ops)
+	}
+	r.clock = now
+	r.Game.score.Update(r.Game.play.Score())
+}
+
+func (r *replays) Layout(gtx layout.Context) layout.Dimensions {
+	r.init()
+	r.update(gtx)
+	if r.viewing {
+		return r.layoutViewer(gtx)
+	}
+	return layout.Center.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		gtx.Constraints.Max.X /= 2
+		gtx.Constraints.Min.X = gtx.Constraints.Max.X
+		return r.Menu.Layout(gtx, replays_, func(gtx layout.Context, i int) widgets.MenuItem {
+			switch i {
+			case replaysList:
+				return widgets.MenuTitle(r.layoutList, "Replays")
+			case replaysNewer, replaysOlder:
+				if r.pages() == 1 {
+					return widgets.MenuSpacer(unit.Value{})
+				}
+				txt := "Newer"
+				if i == replaysOlder {
+					txt = "Older"
+				}
+				return widgets.MenuButton(func(gtx layout.Context) layout.Dimensions {
+					return r.Menu.Label.Layout(gtx, txt)
+				})
+			case replaysSpace:
+				return widgets.MenuSpacer(unit.Dp(20))
+			case replaysBack:
+				return widgets.MenuButton(func(gtx layout.Context) layout.Dimensions {
+					return r.Menu.Label.Layout(gtx, "Back")
+				})
+			}
+			return widgets.MenuItem{}
+		})
+	})
+}
+
+func (r *replays) layoutList(gtx layout.Context) layout.Dimensions {
+	if len(r.entries) == 0 {
+		return layout.Center.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+			return r.Menu.Label.Layout(gtx, "No replay")
+		})
+	}
+	entries := r.listed()
+	return r.table.Layout(gtx, len(entries), func(gtx layout.Context, idx int) layout.Dimensions {
+		e := &entries[idx]
+		cell := func(dir layout.Direction, txt string) layout.FlexChild {
+			return layout.Flexed(1./3, func(gtx layout.Context) layout.Dimensions {
+				return layout.Inset{
+					Left:  r.Padding,
+					Right: r.Padding,
+				}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					return dir.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+						return r.Menu.Label.Layout(gtx, txt)
+					})
+				})
+			})
+		}
+		return layout.Flex{
+			Axis:    layout.Horizontal,
+			Spacing: layout.SpaceBetween,
+		}.Layout(gtx,
+			cell(layout.W, e.date.Format("2006-01-02 15:04")),
+			cell(layout.Center, e.kind.String()),
+			cell(layout.E, replayValueText(e.value)),
+		)
+	})
+}
+
+// replayValueText returns the text of the ranking value, still being computed if empty.
+func replayValueText(v string) string {
+	if v == "" {
+		return "..."
+	}
+	return v
+}
+
+func (r *replays) layoutViewer(gtx layout.Context) layout.Dimensions {
+	return layout.Flex{
+		Axis: layout.Vertical,
+	}.Layout(gtx,
+		layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+			r.Game.init(gtx)
+			r.Game.setGridCellSize(gtx)
+			return r.Game.layoutPlay(gtx, false)
+		}),
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return layout.Center.Layout(gtx, r.layoutControls)
+		}),
+	)
+}
+
+func (r *replays) layoutControls(gtx layout.Context) layout.Dimensions {
+	return r.controls.Layout(gtx, viewer_, func(gtx layout.Context, i int) widgets.MenuItem {
+		l := r.controls.Label
+		var txt string
+		switch i {
+		case viewerBack:
+			txt = "Back"
+		case viewerRestart:
+			txt = "Restart"
+		case viewerRewind:
+			txt = fmt.Sprintf("-%v", replaySkip)
+		case viewerPlay:
+			txt = "Pause"
+			if r.paused {
+				txt = "Play"
+			}
+		case viewerStep:
+			txt = "Step"
+		case viewerForward:
+			txt = fmt.Sprintf("+%v", replaySkip)
+		case viewerSpeed:
+			txt = fmt.Sprintf("x%v", replaySpeeds[r.speed])
+		case viewerTime:
+			now := int(r.playback.Time() / time.Millisecond)
+			end := int(r.playback.Replay.Time() / time.Millisecond)
+			txt = scoreValue(scoreTime, now) + " / " + scoreValue(scoreTime, end)
+			if s := r.Game.play.State(); s != engine.StateRunning && s != engine.StateClearing {
+				txt += " " + s.String()
+			}
+			return widgets.MenuNoTitle(func(gtx layout.Context) layout.Dimensions {
+				return layout.UniformInset(r.Padding).Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					return l.Layout(gtx, txt)
+				})
+			})
+		}
+		return widgets.MenuButton(func(gtx layout.Context) layout.Dimensions {
+			return layout.Inset{
+				Left:  r.Padding,
+				Right: r.Padding,
+			}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+				return l.Layout(gtx, txt)
+			})
+		})
+	})
+}
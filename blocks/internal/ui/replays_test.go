@@ -0,0 +1,112 @@
+package ui
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gioui.org/app"
+
+	"github.com/pierrec/games/blocks/internal/engine"
+)
+
+func TestLoadReplay(t *testing.T) {
+	var g engine.Game
+	g.Start(engine.Config{Seed: 1, Mode: engine.ModeSurvival})
+	for i := 0; i < 10; i++ {
+		g.Step(engine.DropHard, time.Second)
+	}
+	date := time.Date(2021, 5, 1, 15, 4, 5, 123e6, time.Local)
+	fName := filepath.Join(t.TempDir(), replayPrefix+date.Format(replayTime)+replayExt)
+	f, err := os.Create(fName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := g.Replay().WriteTo(f); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	e, err := loadReplay(fName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !e.date.Equal(date) {
+		t.Errorf("got %v; want %v", e.date, date)
+	}
+	if got, want := e.kind, (scoreKind{Mode: engine.ModeSurvival}); got != want {
+		t.Errorf("got %v; want %v", got, want)
+	}
+	sc := g.Score()
+	want := scoreValue(scoreTime, int(sc.Time/time.Millisecond)) + " - " + scoreValue(scoreLines, sc.Lines) + " lines"
+	if got := replayValue(e.replay); got != want {
+		t.Errorf("got %q; want %q", got, want)
+	}
+}
+
+// setDataDir sets the data directory to a temporary one for the test duration.
+func setDataDir(t *testing.T) string {
+	dir := t.TempDir()
+	dataDir = func() (string, error) { return dir, nil }
+	t.Cleanup(func() { dataDir = app.DataDir })
+	return dir
+}
+
+func TestLoadReplays(t *testing.T) {
+	dir := setDataDir(t)
+
+	var g engine.Game
+	g.Start(engine.Config{Seed: 1})
+	g.Step(engine.DropHard, time.Second)
+	var buf bytes.Buffer
+	if _, err := g.Replay().WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	date := time.Date(2021, 5, 1, 15, 4, 5, 0, time.Local)
+	write := func(i int, data []byte) {
+		name := replayPrefix + date.Add(time.Duration(i)*time.Minute).Format(replayTime) + replayExt
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The invalid replay is reported and skipped.
+	write(0, []byte("invalid"))
+	for i := 1; i <= replaysPage+1; i++ {
+		write(i, buf.Bytes())
+	}
+
+	var r replays
+	defer r.Stop()
+	if err := r.Load(); err == nil {
+		t.Error("expected error")
+	}
+	if got, want := len(r.entries), replaysPage+1; got != want {
+		t.Fatalf("got %d replays; want %d", got, want)
+	}
+	if got, want := r.entries[0].date, date.Add((replaysPage+1)*time.Minute); !got.Equal(want) {
+		t.Errorf("got newest %v; want %v", got, want)
+	}
+	if got, want := len(r.listed()), replaysPage; got != want {
+		t.Errorf("got %d replays listed; want %d", got, want)
+	}
+	r.turn(true)
+	if got, want := r.listed()[0].date, date.Add(time.Minute); len(r.listed()) != 1 || !got.Equal(want) {
+		t.Errorf("got %d replays listed, oldest %v; want 1, %v", len(r.listed()), got, want)
+	}
+
+	// The scores are computed in the background.
+	for r.pending > 0 {
+		s := <-r.scores
+		r.entries[s.index].value = s.value
+		r.pending--
+	}
+	for i, e := range r.entries {
+		if e.value == "" {
+			t.Errorf("%d: no score", i)
+		}
+	}
+}
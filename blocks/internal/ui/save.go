@@ -0,0 +1,154 @@
+package ui
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/pierrec/games/blocks/internal/engine"
+)
+
+// saveFile is the file name of the game left unfinished, next to the config file.
+const saveFile = "blocks.save"
+
+// savedGame is a game left unfinished. Since the game is deterministic,
+// its whole state, including the randomizer one, is restored by playing its replay.
+type savedGame struct {
+	Replay []byte `json:"replay"` // replay in its binary format
+	Botted bool   `json:"botted"` // whether or not the bot played the game
+	Hinted bool   `json:"hinted"` // whether or not hints were shown during the game
+}
+
+// savePath returns the path of the saved game file.
+func savePath() (string, error) {
+	dir, err := dataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, saveFile), nil
+}
+
+// hasSavedGame reports whether or not there is a game to be resumed.
+func hasSavedGame() bool {
+	fName, err := savePath()
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(fName)
+	return err == nil
+}
+
+// save returns the saved game data.
+func (ui *game) save() ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := ui.play.Replay().WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return json.Marshal(savedGame{
+		Replay: buf.Bytes(),
+		Botted: ui.botted,
+		Hinted: ui.hinted,
+	})
+}
+
+// loadGame decodes the saved game data.
+func loadGame(bts []byte) (r engine.Replay, saved savedGame, err error) {
+	if err = json.Unmarshal(bts, &saved); err != nil {
+		return
+	}
+	_, err = r.ReadFrom(bytes.NewReader(saved.Replay))
+	return
+}
+
+// saveGame writes the current game if it is not over, so that it can be resumed.
+func (ui *UI) saveGame() (err error) {
+	switch ui.game.play.State() {
+	case engine.StateRunning, engine.StateClearing:
+	default:
+		return nil
+	}
+	r := ui.game.play.Replay()
+	if len(r.Steps) == 0 {
+		return nil
+	}
+	fName, err := savePath()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("saved game in %s: %w", fName, err)
+		}
+	}()
+	bts, err := ui.game.save()
+	if err != nil {
+		return
+	}
+	if err = savePendingReplay(fName); err != nil {
+		return
+	}
+	if err = os.WriteFile(fName, bts, 0644); err != nil {
+		return
+	}
+	ui.home.Resumable = true
+	return
+}
+
+// savePendingReplay writes the replay of the game saved in fName, if any,
+// so that it is not lost when overwritten.
+func savePendingReplay(fName string) error {
+	bts, err := os.ReadFile(fName)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	r, _, err := loadGame(bts)
+	if err != nil {
+		// Nothing to keep from an invalid saved game.
+		return nil
+	}
+	return writeReplay(&r)
+}
+
+// resumeGame restores the saved game, paused, and removes it
+// so that it can only be resumed once, even if it is invalid.
+// A game with custom blocks is kept until their colors can be loaded.
+func (ui *UI) resumeGame() (err error) {
+	ui.home.Resumable = false
+	fName, err := savePath()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("saved game in %s: %w", fName, err)
+		}
+	}()
+	bts, err := os.ReadFile(fName)
+	if err != nil {
+		return
+	}
+	r, saved, err := loadGame(bts)
+	if err != nil {
+		_ = os.Remove(fName)
+		return
+	}
+	cfg := r.Config
+	if cfg.Pieces == engine.PiecesCustom {
+		// The blocks are the saved ones, only their colors are loaded.
+		if _, err = ui.loadCustomBlocks(cfg.Cols, cfg.Rows, cfg.Hidden); err != nil {
+			ui.home.Resumable = true
+			return
+		}
+		ui.game.Colors = ui.customBlocks
+	}
+	if err = os.Remove(fName); err != nil {
+		return
+	}
+	ui.game.Resume(&r, saved.Botted, saved.Hinted)
+	return
+}
@@ -0,0 +1,154 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pierrec/games/blocks/internal/engine"
+)
+
+func TestResumeGame(t *testing.T) {
+	var g game
+	g.Randomizer = engine.RandomTGM
+	g.Mode = engine.ModeSurvival
+	g.Previews = 3
+	g.Start()
+	defer g.Stop()
+	for i, a := range []engine.Action{engine.MoveLeft, engine.DropHard, engine.Hold, engine.RotateRight, engine.DropHard, engine.None} {
+		g.step(a, time.Duration(i)*300*time.Millisecond)
+	}
+	g.botted = true
+	bts, err := g.save()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, saved, err := loadGame(bts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var resumed game
+	resumed.Resume(&r, saved.Botted, saved.Hinted)
+	defer resumed.Stop()
+	if got, want := resumed.state, gamePaused; got != want {
+		t.Errorf("got state %v; want %v", got, want)
+	}
+	if !resumed.Botted() {
+		t.Error("bot flag lost")
+	}
+	want, got := &g.play, &resumed.play
+	if got.Board().String() != want.Board().String() {
+		t.Errorf("got board\n%s\nwant\n%s", got.Board(), want.Board())
+	}
+	for _, c := range []struct {
+		name      string
+		got, want interface{}
+	}{
+		{"score", got.Score(), want.Score()},
+		{"current", got.Current(), want.Current()},
+		{"queue", got.Queue(), want.Queue()},
+		{"hold", got.Hold(), want.Hold()},
+		{"config", got.Config(), want.Config()},
+		{"garbage", fst(got.NextGarbage()), fst(want.NextGarbage())},
+	} {
+		if !reflect.DeepEqual(c.got, c.want) {
+			t.Errorf("%s: got %v; want %v", c.name, c.got, c.want)
+		}
+	}
+	// Both games keep dealing the same pieces.
+	for i := 0; i < 10; i++ {
+		want.Step(engine.DropHard, time.Second)
+		got.Step(engine.DropHard, time.Second)
+	}
+	if got.Board().String() != want.Board().String() {
+		t.Errorf("got board\n%s\nwant\n%s", got.Board(), want.Board())
+	}
+}
+
+func TestSaveGameReplay(t *testing.T) {
+	dir := setDataDir(t)
+
+	var ui UI
+	for i := 0; i < 2; i++ {
+		ui.game.Start()
+		ui.game.step(engine.DropHard, time.Second)
+		if err := ui.saveGame(); err != nil {
+			t.Fatal(err)
+		}
+		ui.game.Stop()
+	}
+	// The game saved first is kept as a replay.
+	files, err := filepath.Glob(filepath.Join(dir, replayPrefix+"*"+replayExt))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(files), 1; got != want {
+		t.Fatalf("got %d replays; want %d", got, want)
+	}
+	if !ui.home.Resumable || !hasSavedGame() {
+		t.Error("no game to be resumed")
+	}
+}
+
+func TestResumeInvalidGame(t *testing.T) {
+	dir := setDataDir(t)
+
+	if err := os.WriteFile(filepath.Join(dir, saveFile), []byte("invalid"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	var ui UI
+	ui.home.Resumable = hasSavedGame()
+	if err := ui.resumeGame(); err == nil {
+		t.Fatal("expected error")
+	}
+	// The invalid game is not offered again.
+	if ui.home.Resumable || hasSavedGame() {
+		t.Error("invalid game still to be resumed")
+	}
+}
+
+func TestResumeCustomGame(t *testing.T) {
+	dir := setDataDir(t)
+	shapes, _, err := readCustomBlocks(strings.NewReader("RR\nRR\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ui := UI{Blocks: "blocks.txt"}
+	ui.game.Pieces = engine.PiecesCustom
+	ui.game.Shapes = shapes
+	ui.game.Start()
+	ui.game.step(engine.DropHard, time.Second)
+	if err := ui.saveGame(); err != nil {
+		t.Fatal(err)
+	}
+	ui.game.Stop()
+
+	// The game is kept while its blocks colors cannot be loaded.
+	if err := ui.resumeGame(); err == nil {
+		t.Fatal("expected error")
+	}
+	if !ui.home.Resumable || !hasSavedGame() {
+		t.Fatal("no game to be resumed")
+	}
+	if err := os.WriteFile(filepath.Join(dir, ui.Blocks), []byte("GG\nGG\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ui.resumeGame(); err != nil {
+		t.Fatal(err)
+	}
+	defer ui.game.Stop()
+	if got, want := ui.game.Colors, (customColors{{{greenT, greenT}, {greenT, greenT}}}); !reflect.DeepEqual(got, want) {
+		t.Errorf("got colors %v; want %v", got, want)
+	}
+	if ui.home.Resumable || hasSavedGame() {
+		t.Error("game still to be resumed")
+	}
+}
+
+func fst(d time.Duration, _ bool) time.Duration {
+	return d
+}
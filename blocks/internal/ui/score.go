@@ -1,13 +1,20 @@
 package ui
 
 import (
+	"fmt"
+	"image"
 	"image/color"
 	"strconv"
 	"time"
 
+	"gioui.org/f32"
 	"gioui.org/layout"
+	"gioui.org/op"
+	"gioui.org/op/clip"
+	"gioui.org/op/paint"
 	"gioui.org/unit"
 
+	"github.com/pierrec/games/blocks/internal/engine"
 	"github.com/pierrec/games/blocks/internal/widgets"
 )
 
@@ -19,11 +26,14 @@ type score struct {
 	LineHeight   unit.Value
 	LineOverflow unit.Value
 	AnimBg       color.NRGBA
-
-	data  [score_]scoreData
-	table widgets.Table
-
-	clears int
+	TimeLimit    time.Duration // count the time down if set
+
+	data      [score_]scoreData
+	table     widgets.Table
+	pieces    int          // number of locked blocks
+	last      engine.Clear // outcome of the last locked block
+	clear     string       // last notable clear
+	clearAnim widgets.Anim // display of the last notable clear
 }
 
 type scoreData struct {
@@ -42,6 +52,9 @@ const (
 	scoreLine2
 	scoreLine3
 	scoreLine4
+	scoreCombo
+	scoreB2B
+	scoreTime
 	score_
 )
 
@@ -53,62 +66,56 @@ var scoreFields = [...]scoreData{
 	scoreLine2: {text: "2 LINES"},
 	scoreLine3: {text: "3 LINES"},
 	scoreLine4: {text: "4 LINES"},
+	scoreCombo: {text: "COMBO"},
+	scoreB2B:   {text: "B2B"},
+	scoreTime:  {text: "TIME"},
 }
 
-// https://tetris.wiki/Tetris_(NES,_Nintendo)
-func gameGravity(l int) time.Duration {
-	g := 1
-	switch {
-	case l <= 8:
-		g = 48 - 5*l
-	case l == 9:
-		g = 6
-	case l <= 12:
-		g = 5
-	case l <= 15:
-		g = 4
-	case l <= 18:
-		g = 3
-	case l <= 28:
-		g = 2
+// scoreValue returns the text for the value of the score field.
+func scoreValue(field, v int) string {
+	switch field {
+	case scoreTime:
+		// Milliseconds.
+		return fmt.Sprintf("%d:%02d.%03d", v/60000, v/1000%60, v%1000)
 	}
-	// 30 frames ~ 1500ms
-	return time.Duration(g*1500/30) * time.Millisecond
+	return strconv.Itoa(v)
 }
 
-// https://tetris.wiki/Scoring#Original_Nintendo_scoring_system
-func (s *score) NewBlock(softDrop int) {
-	s.data[scoreTotal].val += softDrop
-}
-
-func (s *score) NewLines(num int) (newLevel bool) {
-	points := [4]int{40, 100, 300, 1200}
+// Update sets the score data from the game score.
+func (s *score) Update(sc engine.Score) {
+	s.init()
 	total := s.data[scoreTotal].val
-	pts := points[num-1] * (s.data[scoreLevel].val + 1)
-	s.data[scoreTotal].val += pts
 	// Flash the total score every 10k points.
-	if threshold := 10000; (total+pts)/threshold > total/threshold {
+	if threshold := 10000; sc.Total/threshold > total/threshold {
 		s.data[scoreTotal].animate = true
 	}
-	s.data[scoreLines].val += num
-	s.data[scoreLine1+num-1].val++
-	// Level change check.
-	clears := s.clears + num
-	startLevel := s.data[scoreLevel].val
-	if clears >= (startLevel*10)+10 || clears >= max(100, (startLevel*10)-50) {
-		// Change level: make it flash.
-		s.data[scoreLevel].val++
-		s.clears = 0
+	s.data[scoreTotal].val = sc.Total
+	s.data[scoreLines].val = sc.Lines
+	if sc.Level != s.data[scoreLevel].val {
+		// Level changed: make it flash.
+		s.data[scoreLevel].val = sc.Level
 		s.data[scoreLevel].animate = true
-		return true
 	}
-	s.clears = clears
-	return false
-}
-
-func (s *score) CurrentLevel() int {
-	s.init()
-	return s.data[scoreLevel].val
+	for i, n := range sc.Clears {
+		s.data[scoreLine1+i].val = n
+	}
+	s.data[scoreCombo].val = sc.Combo
+	s.data[scoreB2B].val = sc.B2B
+	t := sc.Time
+	if s.TimeLimit > 0 {
+		t = s.TimeLimit - t
+	}
+	s.data[scoreTime].val = int(t / time.Millisecond)
+	// The last clear changes when a block is locked and when its lines are cleared.
+	if sc.Pieces != s.pieces || sc.Last != s.last {
+		s.pieces = sc.Pieces
+		s.last = sc.Last
+		if sc.Last.Notable() {
+			// Display the clear for 10*200ms.
+			s.clear = sc.Last.String()
+			s.clearAnim.Start(0, 10)
+		}
+	}
 }
 
 func (s *score) Scores() []scoreData {
@@ -150,6 +157,7 @@ func (s *score) init() {
 		for i := range s.data {
 			s.data[i].anim.Next = s.anim
 		}
+		s.clearAnim.Next = s.anim
 		s.table = widgets.Table{
 			LineColor:  s.LineColor,
 			LineHeight: s.LineHeight,
@@ -159,6 +167,35 @@ func (s *score) init() {
 
 func (s *score) Layout(gtx layout.Context) layout.Dimensions {
 	s.init()
+	return layout.Stack{}.Layout(gtx,
+		layout.Stacked(s.layoutTable),
+		layout.Expanded(s.layoutClear),
+	)
+}
+
+// layoutClear displays the last notable clear over the score table.
+func (s *score) layoutClear(gtx layout.Context) layout.Dimensions {
+	if !s.clearAnim.Animating() {
+		return layout.Dimensions{}
+	}
+	s.clearAnim.Animate(gtx)
+	size := gtx.Constraints.Min
+	gtx.Constraints.Min = image.Point{}
+	macro := op.Record(gtx.Ops)
+	dims := layout.UniformInset(s.Padding).Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return s.Label.Layout(gtx, s.clear)
+	})
+	call := macro.Stop()
+	// Center the label on a band across the table.
+	defer op.Save(gtx.Ops).Load()
+	op.Offset(f32.Pt(0, float32(size.Y-dims.Size.Y)/2)).Add(gtx.Ops)
+	paint.FillShape(gtx.Ops, s.AnimBg, clip.Rect{Max: image.Pt(size.X, dims.Size.Y)}.Op())
+	op.Offset(f32.Pt(float32(size.X-dims.Size.X)/2, 0)).Add(gtx.Ops)
+	call.Add(gtx.Ops)
+	return layout.Dimensions{Size: size}
+}
+
+func (s *score) layoutTable(gtx layout.Context) layout.Dimensions {
 	defer func(h unit.Value) { s.table.LineHeight = h }(s.table.LineHeight)
 	return layout.Center.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
 		return s.table.Layout(gtx, len(s.data), func(gtx layout.Context, idx int) layout.Dimensions {
@@ -184,7 +221,7 @@ func (s *score) Layout(gtx layout.Context) layout.Dimensions {
 					return layout.Inset{Right: pad}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
 						return layout.E.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
 							l := s.label(gtx, 1, idx)
-							return l.Layout(gtx, strconv.Itoa(line.val))
+							return l.Layout(gtx, scoreValue(idx, line.val))
 						})
 					})
 				}),
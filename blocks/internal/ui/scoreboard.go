@@ -1,8 +1,8 @@
 package ui
 
 import (
-	"sort"
-	"strconv"
+	"fmt"
+	"time"
 
 	"gioui.org/layout"
 	"gioui.org/op/clip"
@@ -11,6 +11,7 @@ import (
 	"gioui.org/unit"
 	"gioui.org/widget"
 
+	"github.com/pierrec/games/blocks/internal/engine"
 	"github.com/pierrec/games/blocks/internal/widgets"
 )
 
@@ -19,16 +20,126 @@ type scoreEntry struct {
 	Score  [score_]int `json:"score"`
 }
 
+// scoreTable keeps the 10 best entries and 1 as scratch.
+type scoreTable [10 + 1]scoreEntry
+
 type scoreboard struct {
 	Menu     widgets.Menu
 	Padding  unit.Value
+	Kind     scoreKind // kind of games of the displayed entries
 	state    uint8
 	table    widgets.Table
-	data     [10 + 1]scoreEntry // keep the last 10 entries and 1 as scratch
+	data     map[string]*scoreTable // entries by scoreKind key
 	ed       widget.Editor
 	newScore int
 }
 
+// scoreKind identifies the games sharing the same scores.
+type scoreKind struct {
+	Mode      engine.Mode
+	TimeLimit time.Duration   // Ultra mode duration
+	Garbage   int             // Cheese Race garbage lines
+	Hints     bool            // whether or not the placement hints were shown
+	Cols      int             // board width, engine.Cols if not set
+	Rows      int             // board height, engine.Rows if not set
+	Hidden    int             // board hidden rows, engine.HiddenRows if not set
+	Pieces    engine.PieceSet // block set, engine.PiecesStandard if not set
+}
+
+// newScoreKind returns the kind of the games played with the config,
+// only keeping the settings relevant to its mode.
+func newScoreKind(cfg engine.Config) scoreKind {
+	k := scoreKind{Mode: cfg.Mode, Pieces: cfg.Pieces}
+	// Games on the default board do not set its dimensions.
+	if cfg.Cols != engine.Cols || cfg.Rows != engine.Rows || cfg.Hidden != engine.HiddenRows {
+		k.Cols, k.Rows, k.Hidden = cfg.Cols, cfg.Rows, cfg.Hidden
+	}
+	switch cfg.Mode {
+	case engine.ModeUltra:
+		k.TimeLimit = cfg.TimeLimit
+	case engine.ModeCheese:
+		k.Garbage = cfg.Garbage
+	}
+	return k
+}
+
+// key returns the key of the scores in the config.
+func (k scoreKind) key() string {
+	return k.String()
+}
+
+func (k scoreKind) String() string {
+	s := k.Mode.String()
+	switch k.Mode {
+	case engine.ModeUltra:
+		s = fmt.Sprintf("%v %dmin", k.Mode, int(k.TimeLimit.Minutes()))
+	case engine.ModeCheese:
+		s = fmt.Sprintf("%v %d lines", k.Mode, k.Garbage)
+	}
+	if cols, rows, hidden := k.board(); cols != engine.Cols || rows != engine.Rows || hidden != engine.HiddenRows {
+		s += fmt.Sprintf(" %dx%d", cols, rows)
+		if hidden != engine.HiddenRows {
+			s += fmt.Sprintf("+%d", hidden)
+		}
+	}
+	if k.Pieces != engine.PiecesStandard {
+		s += " " + k.Pieces.String()
+	}
+	if k.Hints {
+		s += " (hints)"
+	}
+	return s
+}
+
+// board returns the board dimensions of the games.
+func (k scoreKind) board() (cols, rows, hidden int) {
+	cols, rows, hidden = k.Cols, k.Rows, k.Hidden
+	if cols == 0 {
+		cols = engine.Cols
+	}
+	if rows == 0 {
+		rows = engine.Rows
+	}
+	if hidden == 0 {
+		hidden = engine.HiddenRows
+	}
+	return
+}
+
+// race reports whether or not the games of the mode are ranked by their time,
+// only completed ones making it in the board.
+func race(mode engine.Mode) bool {
+	return mode == engine.ModeSprint || mode == engine.ModeCheese
+}
+
+// better reports whether or not the entry a ranks before b in the game mode.
+func better(mode engine.Mode, a, b *scoreEntry) bool {
+	ta, tb := a.Score[scoreTime], b.Score[scoreTime]
+	switch {
+	case race(mode):
+		// Fastest times first, empty entries last.
+		return tb == 0 || ta > 0 && ta < tb
+	case mode == engine.ModeSurvival:
+		// Longest times first, then most lines.
+		return ta > tb || ta == tb && a.Score[scoreLines] > b.Score[scoreLines]
+	}
+	return a.Score[scoreTotal] > b.Score[scoreTotal]
+}
+
+// scores returns the entries for the kind of games.
+func (s *scoreboard) scores(kind scoreKind) *scoreTable {
+	key := kind.key()
+	t, ok := s.data[key]
+	if !ok {
+		if s.data == nil {
+			s.data = make(map[string]*scoreTable)
+		}
+		t = new(scoreTable)
+		s.data[key] = t
+	}
+	return t
+}
+
 const (
 	scoreboardShow = iota
 	scoreboardPlayer
@@ -42,44 +153,86 @@ const (
 	scoreboard_
 )
 
-func (s *scoreboard) totalAt(i int) int {
-	return s.data[i].Score[scoreTotal]
+// valueAt returns the ranking value of the displayed entry at i.
+func (s *scoreboard) valueAt(i int) string {
+	return rankValue(s.Kind.Mode, &s.scores(s.Kind)[i])
 }
 
-// NewScore returns whether or not the score makes it in the board.
-func (s *scoreboard) NewScore(score []scoreData) bool {
-	total := score[scoreTotal].val
-	if total > 0 {
-		for i := range s.data {
-			switch t := s.totalAt(i); {
-			case t < total:
-			case t == total && i < len(s.data)-1:
-			default:
-				continue
-			}
-			// Add the new score.
-			s.state = scoreboardPlayer
-			var e scoreEntry
-			for i, d := range score {
-				e.Score[i] = d.val
-			}
-			s.data[len(s.data)-1] = e
-			sort.SliceStable(s.data[:], func(i, j int) bool {
-				return s.totalAt(j) < s.totalAt(i) // reverse
-			})
-			s.newScore = i
-			return true
+// rankValue returns the value the entry is ranked by in the game mode.
+func rankValue(mode engine.Mode, e *scoreEntry) string {
+	switch {
+	case race(mode):
+		return scoreValue(scoreTime, e.Score[scoreTime])
+	case mode == engine.ModeSurvival:
+		return fmt.Sprintf("%s - %s lines",
+			scoreValue(scoreTime, e.Score[scoreTime]),
+			scoreValue(scoreLines, e.Score[scoreLines]))
+	}
+	return scoreValue(scoreTotal, e.Score[scoreTotal])
+}
+
+// newScoreEntry returns the entry for the score data.
+func newScoreEntry(score []scoreData) scoreEntry {
+	var e scoreEntry
+	for i, d := range score {
+		e.Score[i] = d.val
+	}
+	return e
+}
+
+// NewScore returns whether or not the score of a game of the given kind makes it in the board,
+// in which case the board displays the scores for that kind.
+// Done reports whether or not the game reached the goal of its mode.
+func (s *scoreboard) NewScore(kind scoreKind, score []scoreData, done bool) bool {
+	e := newScoreEntry(score)
+	mode := kind.Mode
+	switch {
+	case race(mode):
+		if !done {
+			return false
+		}
+	case mode == engine.ModeSurvival:
+		if e.Score[scoreTime] == 0 {
+			return false
 		}
+	case e.Score[scoreTotal] == 0:
+		return false
+	}
+	t := s.scores(kind)
+	n := len(t) - 1
+	for i := range t[:n] {
+		if !better(mode, &e, &t[i]) {
+			continue
+		}
+		// Add the new score.
+		copy(t[i+1:], t[i:n])
+		t[i] = e
+		s.Kind = kind
+		s.state = scoreboardPlayer
+		s.newScore = i
+		return true
 	}
 	return false
 }
 
 func (s *scoreboard) saveConfig(cfg *config) {
-	cfg.Scores = s.data[:len(s.data)-1]
+	cfg.Boards = make(map[string][]scoreEntry)
+	for key, t := range s.data {
+		cfg.Boards[key] = t[:len(t)-1]
+	}
+	// Older versions only know about the marathon scores.
+	marathon := scoreKind{Mode: engine.ModeMarathon}.key()
+	cfg.Scores = cfg.Boards[marathon]
+	delete(cfg.Boards, marathon)
 }
 
 func (s *scoreboard) loadConfig(cfg *config) {
-	copy(s.data[:], cfg.Scores)
+	copy(s.scores(scoreKind{Mode: engine.ModeMarathon})[:], cfg.Scores)
+	for key, entries := range cfg.Boards {
+		t := new(scoreTable)
+		copy(t[:], entries)
+		s.data[key] = t
+	}
 }
 
 func (s *scoreboard) init() {
@@ -112,7 +265,7 @@ func (s *scoreboard) update() {
 			switch e := e.(type) {
 			case widget.SubmitEvent:
 				s.state = scoreboardShow
-				s.data[s.newScore].Player = e.Text
+				s.scores(s.Kind)[s.newScore].Player = e.Text
 				s.ed.SetText("")
 			}
 		}
@@ -128,7 +281,14 @@ func (s *scoreboard) Layout(gtx layout.Context) layout.Dimensions {
 		return s.Menu.Layout(gtx, scoreboard_, func(gtx layout.Context, i int) widgets.MenuItem {
 			switch i {
 			case scoreboardData:
-				return widgets.MenuTitle(s.layoutScores, "Best Scores")
+				title := "Best Scores"
+				switch {
+				case race(s.Kind.Mode):
+					title = "Fastest Times"
+				case s.Kind.Mode == engine.ModeSurvival:
+					title = "Longest Survivals"
+				}
+				return widgets.MenuTitle(s.layoutScores, s.Kind.String()+" - "+title)
 			case scoreboardSpace:
 				return widgets.MenuSpacer(unit.Dp(20))
 			case scoreboardBack:
@@ -142,8 +302,9 @@ func (s *scoreboard) Layout(gtx layout.Context) layout.Dimensions {
 }
 
 func (s *scoreboard) layoutScores(gtx layout.Context) layout.Dimensions {
+	t := s.scores(s.Kind)
 	noScore := true
-	for _, sc := range s.data {
+	for _, sc := range t {
 		if sc != (scoreEntry{}) {
 			noScore = false
 			break
@@ -154,7 +315,7 @@ func (s *scoreboard) layoutScores(gtx layout.Context) layout.Dimensions {
 			return s.Menu.Label.Layout(gtx, "No score")
 		})
 	}
-	data := s.data[:len(s.data)-1] // only display the first 10 entries
+	data := t[:len(t)-1] // only display the first 10 entries
 	n := len(data)
 	return s.table.Layout(gtx, n, func(gtx layout.Context, idx int) layout.Dimensions {
 		line := data[idx]
@@ -199,7 +360,7 @@ func (s *scoreboard) layoutScores(gtx layout.Context) layout.Dimensions {
 								if isPlayer {
 									l.Color = s.Menu.Border.Color
 								}
-								return l.Layout(gtx, strconv.Itoa(s.totalAt(idx)))
+								return l.Layout(gtx, s.valueAt(idx))
 							})
 						})
 					}),
@@ -0,0 +1,109 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pierrec/games/blocks/internal/engine"
+)
+
+func TestScoreboardSprint(t *testing.T) {
+	var s scoreboard
+	score := func(ms int) []scoreData {
+		data := scoreFields
+		data[scoreTotal].val = 100
+		data[scoreLines].val = engine.SprintLines
+		data[scoreTime].val = ms
+		return data[:]
+	}
+	for _, tc := range []struct {
+		ms   int
+		done bool
+		ok   bool
+	}{
+		{90000, true, true},
+		{10000, false, false}, // not complete
+		{80000, true, true},
+		{95000, true, true},
+	} {
+		if got, want := s.NewScore(scoreKind{Mode: engine.ModeSprint}, score(tc.ms), tc.done), tc.ok; got != want {
+			t.Fatalf("%v: got %v; want %v", tc, got, want)
+		}
+	}
+	var got []string
+	for i := 0; i < 4; i++ {
+		got = append(got, s.valueAt(i))
+	}
+	want := []string{"1:20.000", "1:30.000", "1:35.000", "0:00.000"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got %v; want %v", got, want)
+		}
+	}
+	// Sprint times are kept apart from the marathon scores.
+	if e := s.scores(scoreKind{Mode: engine.ModeMarathon})[0]; e != (scoreEntry{}) {
+		t.Errorf("unexpected marathon score %v", e)
+	}
+}
+
+func TestScoreKind(t *testing.T) {
+	for _, tc := range []struct {
+		mode  engine.Mode
+		limit time.Duration
+		key   string
+	}{
+		{engine.ModeMarathon, 2 * time.Minute, "Marathon"},
+		{engine.ModeSprint, 3 * time.Minute, "Sprint"},
+		{engine.ModeUltra, 2 * time.Minute, "Ultra 2min"},
+		{engine.ModeUltra, 3 * time.Minute, "Ultra 3min"},
+		{engine.ModeCheese, 3 * time.Minute, "Cheese Race 10 lines"},
+	} {
+		cfg := engine.Config{Mode: tc.mode, TimeLimit: tc.limit, Garbage: engine.CheeseLines}
+		if got, want := newScoreKind(cfg).key(), tc.key; got != want {
+			t.Errorf("got %q; want %q", got, want)
+		}
+	}
+
+	for _, tc := range []struct {
+		cfg engine.Config
+		key string
+	}{
+		{engine.Config{Mode: engine.ModeSprint, Cols: engine.Cols, Rows: engine.Rows, Hidden: engine.HiddenRows}, "Sprint"},
+		{engine.Config{Mode: engine.ModeSprint, Cols: 12, Rows: engine.Rows, Hidden: engine.HiddenRows}, "Sprint 12x20"},
+		{engine.Config{Mode: engine.ModeMarathon, Cols: engine.Cols, Rows: 30, Hidden: 2}, "Marathon 10x30+2"},
+		{engine.Config{Mode: engine.ModeSprint, Cols: engine.Cols, Rows: engine.Rows, Hidden: engine.HiddenRows, Pieces: engine.PiecesBig}, "Sprint Big"},
+		{engine.Config{Mode: engine.ModeMarathon, Cols: 12, Rows: engine.Rows, Hidden: engine.HiddenRows, Pieces: engine.PiecesTiny}, "Marathon 12x20 Tiny"},
+	} {
+		if got, want := newScoreKind(tc.cfg).key(), tc.key; got != want {
+			t.Errorf("got %q; want %q", got, want)
+		}
+	}
+
+	k := scoreKind{Mode: engine.ModeSprint, Hints: true}
+	if got, want := k.key(), "Sprint (hints)"; got != want {
+		t.Errorf("got %q; want %q", got, want)
+	}
+}
+
+func TestScoreboardSurvival(t *testing.T) {
+	var s scoreboard
+	kind := scoreKind{Mode: engine.ModeSurvival}
+	for _, tc := range []struct{ ms, lines int }{
+		{60000, 10},
+		{90000, 5},
+		{60000, 12},
+	} {
+		data := scoreFields
+		data[scoreLines].val = tc.lines
+		data[scoreTime].val = tc.ms
+		if !s.NewScore(kind, data[:], false) {
+			t.Fatalf("%v: score not kept", tc)
+		}
+	}
+	want := []string{"1:30.000 - 5 lines", "1:00.000 - 12 lines", "1:00.000 - 10 lines"}
+	for i, w := range want {
+		if got := s.valueAt(i); got != w {
+			t.Errorf("got %q; want %q", got, w)
+		}
+	}
+}
@@ -1,8 +1,10 @@
 package ui
 
 import (
+	"fmt"
 	"image"
 	"image/color"
+	"time"
 
 	"gioui.org/io/key"
 	"gioui.org/layout"
@@ -12,6 +14,7 @@ import (
 	"gioui.org/widget"
 	"git.sr.ht/~pierrec/giox/widgetx"
 
+	"github.com/pierrec/games/blocks/internal/engine"
 	"github.com/pierrec/games/blocks/internal/widgets"
 )
 
@@ -24,6 +27,7 @@ const (
 	rotateLeft
 	rotateRight
 	pauseGame
+	holdBlock
 )
 
 type keymapEntry struct {
@@ -46,12 +50,101 @@ type settings struct {
 	listC widgetx.ClickList // list of available color textures
 	listP widgetx.ClickList // list of available pattern textures
 	listB layout.List       // list of blocks with the selected texture applied
+
+	random        engine.Random
+	tableRandom   widgets.Table // list of available randomizers
+	rotation      engine.RotationSystem
+	tableRotation widgets.Table // list of available rotation systems
+
+	ghost        bool
+	das          int // index in dasValues
+	arr          int // index in arrValues
+	softDrop     int // index in softDropValues
+	lockDelay    int // index in lockDelayValues
+	lockReset    engine.LockReset
+	previews     int // number of next blocks
+	allSpin      bool
+	scoring      engine.Scoring
+	ultraTime    int             // index in engine.UltraTimes
+	cheeseLines  int             // index in cheeseValues
+	messiness    int             // index in messinessValues
+	boardCols    int             // index in colsValues
+	boardRows    int             // index in rowsValues
+	hiddenRows   int             // index in hiddenValues
+	pieces       engine.PieceSet // block set
+	tableOptions widgets.Table   // list of game options
+}
+
+// Options indexes.
+const (
+	optionGhost = iota
+	optionDAS
+	optionARR
+	optionSoftDrop
+	optionLockDelay
+	optionLockReset
+	optionPreviews
+	optionAllSpin
+	optionScoring
+	optionUltraTime
+	optionCheeseLines
+	optionMessiness
+	optionBoardCols
+	optionBoardRows
+	optionHiddenRows
+	optionPieces
+	option_
+)
+
+// Values for the DAS, ARR and lock delay options in milliseconds, and the soft drop factor.
+var (
+	dasValues       = [...]int{50, 83, 117, 133, 167, 200, 250, 300}
+	arrValues       = [...]int{0, 17, 33, 50, 83, 100}
+	softDropValues  = [...]int{1, 5, 10, 20, 40}
+	lockDelayValues = [...]int{0, 250, 500, 750, 1000}
+)
+
+// Values for the garbage lines of the Cheese Race mode and their messiness in percent.
+var (
+	cheeseValues    = [...]int{5, engine.CheeseLines, 15}
+	messinessValues = [...]int{0, 10, 25, 50, 100}
+)
+
+// Values for the board dimensions.
+var (
+	colsValues   = [...]int{6, 8, engine.Cols, 12, 15, engine.MaxCols}
+	rowsValues   = [...]int{16, engine.Rows, 24, 30, engine.MaxRows}
+	hiddenValues = [...]int{engine.HiddenRows, 2, 3, engine.MaxHidden}
+)
+
+// Default option values.
+const (
+	defaultDAS      = 4 // 167ms
+	defaultARR      = 2 // 33ms
+	defaultSoftDrop = 3 // 20x
+	defaultCheese   = 1 // engine.CheeseLines
+	defaultCols     = 2 // engine.Cols
+	defaultRows     = 1 // engine.Rows
+	defaultHidden   = 0 // engine.HiddenRows
+)
+
+// indexOf returns the index of v in values, or def if not found.
+func indexOf(values []int, v, def int) int {
+	for i, w := range values {
+		if w == v {
+			return i
+		}
+	}
+	return def
 }
 
 // Menu indexes.
 const (
 	settingsKeymap = iota
 	settingsTexture
+	settingsRandomizer
+	settingsRotation
+	settingsOptions
 	settingsSpace
 	settingsBack
 	settings_
@@ -67,20 +160,170 @@ func (s *settings) Key(name string) int {
 	return -1
 }
 
+// KeyName returns the key of the keymap entry.
+func (s *settings) KeyName(k int) string {
+	s.init()
+	return s.keymap[k].Key
+}
+
 func (s *settings) Texture() texture {
 	return s.SelectedColor | s.SelectedPattern
 }
 
+// Randomizer returns the selected piece randomizer.
+func (s *settings) Randomizer() engine.Random {
+	return s.random
+}
+
+// Rotation returns the selected rotation system.
+func (s *settings) Rotation() engine.RotationSystem {
+	return s.rotation
+}
+
+// Ghost returns whether or not the ghost block is displayed.
+func (s *settings) Ghost() bool {
+	return s.ghost
+}
+
+// AutoRepeat returns the Delayed Auto Shift and Auto Repeat Rate for moves
+// and the soft drop speed factor.
+func (s *settings) AutoRepeat() (das, arr time.Duration, softDrop int) {
+	das = time.Duration(dasValues[s.das]) * time.Millisecond
+	arr = time.Duration(arrValues[s.arr]) * time.Millisecond
+	return das, arr, softDropValues[s.softDrop]
+}
+
+// Previews returns the number of next blocks to be displayed.
+func (s *settings) Previews() int {
+	return s.previews
+}
+
+// AllSpin returns whether or not the spins of all blocks are detected, not only the T one.
+func (s *settings) AllSpin() bool {
+	return s.allSpin
+}
+
+// Scoring returns the selected scoring rule.
+func (s *settings) Scoring() engine.Scoring {
+	return s.scoring
+}
+
+// UltraTime returns the game duration in the Ultra mode.
+func (s *settings) UltraTime() time.Duration {
+	return engine.UltraTimes[s.ultraTime]
+}
+
+// Garbage returns the number of garbage lines of the Cheese Race mode
+// and the probability of their holes to change column.
+func (s *settings) Garbage() (lines int, messiness float64) {
+	return cheeseValues[s.cheeseLines], float64(messinessValues[s.messiness]) / 100
+}
+
+// Board returns the width and height of the board, and its number of hidden rows.
+func (s *settings) Board() (cols, rows, hidden int) {
+	return colsValues[s.boardCols], rowsValues[s.boardRows], hiddenValues[s.hiddenRows]
+}
+
+// Pieces returns the selected piece set.
+func (s *settings) Pieces() engine.PieceSet {
+	return s.pieces
+}
+
+// fitBoard widens the board to the narrowest width fitting the blocks, if need be.
+func (s *settings) fitBoard() {
+	n := s.pieces.Size()
+	for s.boardCols < len(colsValues)-1 && colsValues[s.boardCols] < n {
+		s.boardCols++
+	}
+}
+
+// ScoreKind returns the kind of the games played in the mode with the current settings.
+func (s *settings) ScoreKind(mode engine.Mode) scoreKind {
+	lines, _ := s.Garbage()
+	cols, rows, hidden := s.Board()
+	return newScoreKind(engine.Config{
+		Mode:      mode,
+		TimeLimit: s.UltraTime(),
+		Garbage:   lines,
+		Cols:      cols,
+		Rows:      rows,
+		Hidden:    hidden,
+		Pieces:    s.pieces,
+	})
+}
+
+// Lock returns the lock delay and its reset rule.
+func (s *settings) Lock() (time.Duration, engine.LockReset) {
+	return time.Duration(lockDelayValues[s.lockDelay]) * time.Millisecond, s.lockReset
+}
+
 func (s *settings) saveConfig(cfg *config) {
 	cfg.Keys = s.keymap
 	cfg.BlockColor = s.SelectedColor
 	cfg.BlockPattern = s.SelectedPattern
+	cfg.Randomizer = s.random
+	cfg.Rotation = s.rotation
+	cfg.Ghost = s.ghost
+	cfg.DAS = dasValues[s.das]
+	cfg.ARR = arrValues[s.arr]
+	cfg.SoftDrop = softDropValues[s.softDrop]
+	cfg.LockDelay = lockDelayValues[s.lockDelay]
+	cfg.LockReset = s.lockReset
+	cfg.Previews = s.previews
+	cfg.AllSpin = s.allSpin
+	cfg.Scoring = s.scoring
+	cfg.UltraTime = int(s.UltraTime() / time.Second)
+	cfg.CheeseLines = cheeseValues[s.cheeseLines]
+	cfg.Messiness = messinessValues[s.messiness]
+	cfg.BoardCols, cfg.BoardRows, cfg.HiddenRows = s.Board()
+	cfg.Pieces = s.pieces
 }
 
 func (s *settings) loadConfig(cfg *config) {
 	s.keymap = cfg.Keys
 	s.SelectedColor = cfg.BlockColor
 	s.SelectedPattern = cfg.BlockPattern
+	if cfg.Randomizer < engine.Random_ {
+		s.random = cfg.Randomizer
+	}
+	if cfg.Rotation < engine.RotationSystem_ {
+		s.rotation = cfg.Rotation
+	}
+	s.ghost = cfg.Ghost
+	s.das, s.arr, s.softDrop = defaultDAS, defaultARR, defaultSoftDrop
+	// The auto repeat values are missing from older configs.
+	if cfg.DAS > 0 {
+		s.das = indexOf(dasValues[:], cfg.DAS, defaultDAS)
+		s.arr = indexOf(arrValues[:], cfg.ARR, defaultARR)
+		s.softDrop = indexOf(softDropValues[:], cfg.SoftDrop, defaultSoftDrop)
+	}
+	s.lockDelay = indexOf(lockDelayValues[:], cfg.LockDelay, 0)
+	if cfg.LockReset < engine.LockReset_ {
+		s.lockReset = cfg.LockReset
+	}
+	s.allSpin = cfg.AllSpin
+	if cfg.Scoring < engine.Scoring_ {
+		s.scoring = cfg.Scoring
+	}
+	s.ultraTime = 0
+	for i, d := range engine.UltraTimes {
+		if d == time.Duration(cfg.UltraTime)*time.Second {
+			s.ultraTime = i
+		}
+	}
+	s.cheeseLines = indexOf(cheeseValues[:], cfg.CheeseLines, defaultCheese)
+	s.messiness = indexOf(messinessValues[:], cfg.Messiness, 0)
+	s.boardCols = indexOf(colsValues[:], cfg.BoardCols, defaultCols)
+	s.boardRows = indexOf(rowsValues[:], cfg.BoardRows, defaultRows)
+	s.hiddenRows = indexOf(hiddenValues[:], cfg.HiddenRows, defaultHidden)
+	if cfg.Pieces < engine.PieceSet_ {
+		s.pieces = cfg.Pieces
+	}
+	s.fitBoard()
+	s.previews = 1
+	if cfg.Previews > 0 && cfg.Previews <= engine.MaxPreviews {
+		s.previews = cfg.Previews
+	}
 	// If the config file did not exist, initialize the textures.
 	if s.SelectedColor.color() == transparentT {
 		s.SelectedColor = redT
@@ -90,22 +333,38 @@ func (s *settings) loadConfig(cfg *config) {
 
 func (s *settings) init() {
 	if s.table.LineHeight.V == 0 {
-		if s.keymap == nil {
-			s.keymap = []keymapEntry{
-				moveLeft:    {Text: "Move left", Key: key.NameLeftArrow},
-				moveRight:   {Text: "Move right", Key: key.NameRightArrow},
-				dropHard:    {Text: "Hard drop", Key: key.NameUpArrow},
-				dropSoft:    {Text: "Soft drop", Key: key.NameDownArrow},
-				rotateLeft:  {Text: "Rotate left", Key: "A"},
-				rotateRight: {Text: "Rotate right", Key: "Z"},
-				pauseGame:   {Text: "Pause", Key: key.NameEscape},
+		keymap := []keymapEntry{
+			moveLeft:    {Text: "Move left", Key: key.NameLeftArrow},
+			moveRight:   {Text: "Move right", Key: key.NameRightArrow},
+			dropHard:    {Text: "Hard drop", Key: key.NameUpArrow},
+			dropSoft:    {Text: "Soft drop", Key: key.NameDownArrow},
+			rotateLeft:  {Text: "Rotate left", Key: "A"},
+			rotateRight: {Text: "Rotate right", Key: "Z"},
+			pauseGame:   {Text: "Pause", Key: key.NameEscape},
+			holdBlock:   {Text: "Hold", Key: "C"},
+		}
+		// Drop the entries unknown to this version and add the ones
+		// missing from older configs, unless their key is already used.
+		if len(s.keymap) > len(keymap) {
+			s.keymap = s.keymap[:len(keymap)]
+		}
+		for _, e := range keymap[len(s.keymap):] {
+			for _, k := range s.keymap {
+				if k.Key == e.Key {
+					e.Key = ""
+					break
+				}
 			}
+			s.keymap = append(s.keymap, e)
 		}
 		s.table = widgets.Table{
 			Hover:      s.Menu.Border.Color,
 			LineHeight: s.Menu.Border.Width,
 			LineColor:  s.Menu.Border.Color,
 		}
+		s.tableRandom = s.table
+		s.tableRotation = s.table
+		s.tableOptions = s.table
 		s.selected = -1
 		s.listC = widgetx.ClickList{
 			List: layout.List{Alignment: layout.Middle},
@@ -122,6 +381,48 @@ func (s *settings) update(gtx layout.Context) {
 			s.selected = i
 		}
 	}
+	if i := s.tableRandom.Clicked(); i >= 0 {
+		s.random = engine.Random(i)
+	}
+	if i := s.tableRotation.Clicked(); i >= 0 {
+		s.rotation = engine.RotationSystem(i)
+	}
+	switch s.tableOptions.Clicked() {
+	case optionGhost:
+		s.ghost = !s.ghost
+	case optionDAS:
+		s.das = (s.das + 1) % len(dasValues)
+	case optionARR:
+		s.arr = (s.arr + 1) % len(arrValues)
+	case optionSoftDrop:
+		s.softDrop = (s.softDrop + 1) % len(softDropValues)
+	case optionLockDelay:
+		s.lockDelay = (s.lockDelay + 1) % len(lockDelayValues)
+	case optionLockReset:
+		s.lockReset = (s.lockReset + 1) % engine.LockReset_
+	case optionPreviews:
+		s.previews = s.previews%engine.MaxPreviews + 1
+	case optionAllSpin:
+		s.allSpin = !s.allSpin
+	case optionScoring:
+		s.scoring = (s.scoring + 1) % engine.Scoring_
+	case optionUltraTime:
+		s.ultraTime = (s.ultraTime + 1) % len(engine.UltraTimes)
+	case optionCheeseLines:
+		s.cheeseLines = (s.cheeseLines + 1) % len(cheeseValues)
+	case optionMessiness:
+		s.messiness = (s.messiness + 1) % len(messinessValues)
+	case optionBoardCols:
+		s.boardCols = (s.boardCols + 1) % len(colsValues)
+		s.fitBoard()
+	case optionBoardRows:
+		s.boardRows = (s.boardRows + 1) % len(rowsValues)
+	case optionHiddenRows:
+		s.hiddenRows = (s.hiddenRows + 1) % len(hiddenValues)
+	case optionPieces:
+		s.pieces = (s.pieces + 1) % engine.PieceSet_
+		s.fitBoard()
+	}
 	if s.selected >= 0 {
 		key.InputOp{Tag: s}.Add(gtx.Ops)
 		key.FocusOp{Tag: s}.Add(gtx.Ops)
@@ -153,6 +454,12 @@ func (s *settings) Layout(gtx layout.Context) layout.Dimensions {
 				return widgets.MenuTitle(s.layoutKeymap, "Keyboard Map")
 			case settingsTexture:
 				return widgets.MenuTitle(s.layoutTextures, "Texture")
+			case settingsRandomizer:
+				return widgets.MenuTitle(s.layoutRandomizer, "Randomizer")
+			case settingsRotation:
+				return widgets.MenuTitle(s.layoutRotation, "Rotation")
+			case settingsOptions:
+				return widgets.MenuTitle(s.layoutOptions, "Options")
 			case settingsSpace:
 				return widgets.MenuSpacer(unit.Dp(20))
 			case settingsBack:
@@ -209,6 +516,116 @@ func (s *settings) layoutKeymap(gtx layout.Context) layout.Dimensions {
 	})
 }
 
+func (s *settings) layoutRandomizer(gtx layout.Context) layout.Dimensions {
+	return s.layoutChoices(gtx, &s.tableRandom, int(engine.Random_), int(s.random), func(i int) string {
+		return engine.Random(i).String()
+	})
+}
+
+func (s *settings) layoutRotation(gtx layout.Context) layout.Dimensions {
+	return s.layoutChoices(gtx, &s.tableRotation, int(engine.RotationSystem_), int(s.rotation), func(i int) string {
+		return engine.RotationSystem(i).String()
+	})
+}
+
+func (s *settings) layoutOptions(gtx layout.Context) layout.Dimensions {
+	return s.tableOptions.Layout(gtx, option_, func(gtx layout.Context, idx int) layout.Dimensions {
+		var name, value string
+		switch idx {
+		case optionGhost:
+			name, value = "Ghost block", onOff(s.ghost)
+		case optionDAS:
+			name, value = "Auto shift", fmt.Sprintf("%dms", dasValues[s.das])
+		case optionARR:
+			name, value = "Auto repeat", fmt.Sprintf("%dms", arrValues[s.arr])
+		case optionSoftDrop:
+			name, value = "Soft drop", fmt.Sprintf("x%d", softDropValues[s.softDrop])
+		case optionLockDelay:
+			name, value = "Lock delay", "Off"
+			if d := lockDelayValues[s.lockDelay]; d > 0 {
+				value = fmt.Sprintf("%dms", d)
+			}
+		case optionLockReset:
+			name, value = "Lock reset", s.lockReset.String()
+		case optionPreviews:
+			name, value = "Next blocks", fmt.Sprint(s.previews)
+		case optionAllSpin:
+			name, value = "All spins", onOff(s.allSpin)
+		case optionScoring:
+			name, value = "Scoring", s.scoring.String()
+		case optionUltraTime:
+			name, value = "Ultra time", fmt.Sprintf("%dmin", int(s.UltraTime().Minutes()))
+		case optionCheeseLines:
+			name, value = "Cheese lines", fmt.Sprint(cheeseValues[s.cheeseLines])
+		case optionMessiness:
+			name, value = "Messiness", fmt.Sprintf("%d%%", messinessValues[s.messiness])
+		case optionBoardCols:
+			name, value = "Board width", fmt.Sprint(colsValues[s.boardCols])
+		case optionBoardRows:
+			name, value = "Board height", fmt.Sprint(rowsValues[s.boardRows])
+		case optionHiddenRows:
+			name, value = "Hidden rows", fmt.Sprint(hiddenValues[s.hiddenRows])
+		case optionPieces:
+			name, value = "Blocks", s.pieces.String()
+		}
+		return layout.Flex{
+			Axis:    layout.Horizontal,
+			Spacing: layout.SpaceBetween,
+		}.Layout(gtx,
+			layout.Flexed(0.5, func(gtx layout.Context) layout.Dimensions {
+				return layout.Inset{
+					Left: s.Padding,
+				}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					return layout.W.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+						return s.Menu.Label.Layout(gtx, name)
+					})
+				})
+			}),
+			layout.Flexed(0.5, func(gtx layout.Context) layout.Dimensions {
+				return layout.Inset{
+					Right: s.Padding,
+				}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					return layout.E.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+						return s.Menu.Label.Layout(gtx, value)
+					})
+				})
+			}),
+		)
+	})
+}
+
+func onOff(b bool) string {
+	if b {
+		return "On"
+	}
+	return "Off"
+}
+
+// layoutChoices lays out n choices in the table, highlighting the selected one.
+func (s *settings) layoutChoices(gtx layout.Context, table *widgets.Table, n, selected int, name func(int) string) layout.Dimensions {
+	return table.Layout(gtx, n, func(gtx layout.Context, idx int) layout.Dimensions {
+		l := s.Menu.Label
+		if selected == idx {
+			l.Color = s.SelectedFg
+		}
+		return layout.Stack{}.Layout(gtx,
+			layout.Expanded(func(gtx layout.Context) layout.Dimensions {
+				size := gtx.Constraints.Min
+				if selected == idx {
+					paint.FillShape(gtx.Ops, s.SelectedBg, clip.Rect{Max: size}.Op())
+				}
+				return layout.Dimensions{Size: size}
+			}),
+			layout.Stacked(func(gtx layout.Context) layout.Dimensions {
+				gtx.Constraints.Min.X = gtx.Constraints.Max.X
+				return layout.Center.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					return l.Layout(gtx, name(idx))
+				})
+			}),
+		)
+	})
+}
+
 func (s *settings) layoutTextures(gtx layout.Context) layout.Dimensions {
 	const selectedCell, cell = 48, 30
 	return layout.Flex{
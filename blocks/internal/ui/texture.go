@@ -15,6 +15,8 @@ import (
 	"gioui.org/op"
 	"gioui.org/op/clip"
 	"gioui.org/op/paint"
+
+	"github.com/pierrec/games/blocks/internal/colors"
 )
 
 //go:generate go run golang.org/x/tools/cmd/stringer -type texture -linecomment -output texture_string.go
@@ -95,7 +97,7 @@ const (
 
 const (
 	texturePatternBits = 10
-	textureColorMask   = 1<<texturePatternBits - 1
+	textureColorMask   = colors.TextureMask
 )
 
 // Texture patterns.
@@ -113,9 +115,14 @@ const (
 	gradientNET
 	gradientSET
 	gradientSWT
+	outlineT
+	stripesT
 	_patternT
 )
 
+// garbageT is the texture of the garbage cells, set apart from the block ones.
+const garbageT = whiteT | stripesT | blurT
+
 // color extracts the color from the texture.
 func (t texture) color() texture {
 	return t & textureColorMask
@@ -131,12 +138,21 @@ func (t texture) pattern() texture {
 
 // gradient extracts the gradient from the texture.
 func (t texture) gradient() texture {
-	if p := t.pattern(); p >= gradientNT {
+	if p := t.pattern(); p >= gradientNT && p <= gradientSWT {
 		return p
 	}
 	return uniformT
 }
 
+// ghost returns the outlined and blurred texture used to show where a block lands.
+func (t texture) ghost() texture {
+	c := t.color()
+	if c < whiteT || c >= _colorT {
+		c = whiteT
+	}
+	return (c | outlineT).blur()
+}
+
 // blur returns a color which alpha channel is set to 128.
 func (t texture) blur() texture {
 	return t | blurT
@@ -147,27 +163,9 @@ func (t texture) unblur() texture {
 	return t &^ blurT
 }
 
+// nrgba returns the color of the texture, shared with the other front ends.
 func (t texture) nrgba() (c color.NRGBA) {
-	switch t.color() {
-	case whiteT:
-		c = white
-	case blackT:
-		c = black
-	case redT:
-		c = red
-	case orangeT:
-		c = orange
-	case yellowT:
-		c = yellow
-	case greenT:
-		c = green
-	case blueT:
-		c = blue
-	case indigoT:
-		c = indigo
-	case violetT:
-		c = violet
-	}
+	c, _ = colors.Texture(uint16(t))
 	if t&blurT > 0 {
 		c.A = 128
 	}
@@ -340,6 +338,26 @@ func (t texture) layoutPattern(gtx layout.Context, bg color.NRGBA) layout.Dimens
 			},
 		}.Add(gtx.Ops)
 		paint.Fill(gtx.Ops, col)
+	case outlineT:
+		paint.Fill(gtx.Ops, col)
+		clip.RRect{
+			Rect: f32.Rectangle{
+				Min: orig,
+				Max: size32.Sub(orig),
+			},
+		}.Add(gtx.Ops)
+		paint.Fill(gtx.Ops, bg)
+	case stripesT:
+		paint.Fill(gtx.Ops, bg)
+		for y := float32(0); y < size32.Y; y += 2 * height {
+			stack := op.Save(gtx.Ops)
+			clip.Rect{
+				Min: image.Pt(0, int(y)),
+				Max: image.Pt(size.X, int(y+height)),
+			}.Add(gtx.Ops)
+			paint.Fill(gtx.Ops, col)
+			stack.Load()
+		}
 	case pyramidT:
 		paint.Fill(gtx.Ops, col)
 		step := f32.Pt(height/2, height/2)
@@ -35,10 +35,12 @@ func _() {
 	_ = x[gradientNET-11264]
 	_ = x[gradientSET-12288]
 	_ = x[gradientSWT-13312]
-	_ = x[_patternT-14336]
+	_ = x[outlineT-14336]
+	_ = x[stripesT-15360]
+	_ = x[_patternT-16384]
 }
 
-const _texture_name = "T_WbROYGBIVcolgiologoimguniformTsquareThollowTcornerTpyramidTgradientNTgradientETgradientSTgradientWTgradientNWTgradientNETgradientSETgradientSWT_patternT"
+const _texture_name = "T_WbROYGBIVcolgiologoimguniformTsquareThollowTcornerTpyramidTgradientNTgradientETgradientSTgradientWTgradientNWTgradientNETgradientSETgradientSWToutlineTstripesT_patternT"
 
 var _texture_map = map[texture]string{
 	0:     _texture_name[0:1],
@@ -68,7 +70,9 @@ var _texture_map = map[texture]string{
 	11264: _texture_name[112:123],
 	12288: _texture_name[123:134],
 	13312: _texture_name[134:145],
-	14336: _texture_name[145:154],
+	14336: _texture_name[145:153],
+	15360: _texture_name[153:161],
+	16384: _texture_name[161:170],
 }
 
 func (i texture) String() string {
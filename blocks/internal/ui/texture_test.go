@@ -0,0 +1,24 @@
+package ui
+
+import (
+	"image/color"
+	"testing"
+)
+
+// Test that the texture colors match the palette shared with the other front ends.
+func TestTextureColors(t *testing.T) {
+	for _, tc := range []struct {
+		t    texture
+		want color.NRGBA
+	}{
+		{transparentT, color.NRGBA{}},
+		{whiteT, white},
+		{blackT | gradientNT, black},
+		{redT, red},
+		{violetT | blurT, color.NRGBA{R: violet.R, G: violet.G, B: violet.B, A: 128}},
+	} {
+		if got := tc.t.nrgba(); got != tc.want {
+			t.Errorf("%v: got %v; want %v", tc.t, got, tc.want)
+		}
+	}
+}
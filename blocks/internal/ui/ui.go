@@ -7,6 +7,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"time"
 
 	"gioui.org/app"
 	"gioui.org/io/system"
@@ -19,10 +20,11 @@ import (
 	"gioui.org/widget"
 	. "golang.org/x/image/colornames"
 
+	"github.com/pierrec/games/blocks/internal/engine"
 	"github.com/pierrec/games/blocks/internal/widgets"
 )
 
-//go:generate go run golang.org/x/tools/cmd/stringer -type blockID,gameState -linecomment -output ui_string.go
+//go:generate go run golang.org/x/tools/cmd/stringer -type gameState -linecomment -output ui_string.go
 
 type uiState uint8
 
@@ -33,25 +35,55 @@ const (
 	uiGame
 	uiGameOver
 	uiSettings
+	uiReplays
+	uiDemo
 	uiQuit
 )
 
+// dataDir returns the directory of the config, replays and saved game files.
+var dataDir = app.DataDir
+
 type UI struct {
 	Config   string // file name
+	Blocks   string // custom blocks file name
 	theme    theme
 	state    uiState
 	home     home
 	scores   scoreboard
 	game     game
 	settings settings
+	replays  replays
+	demo     demo
+
+	customBlocks customColors // colors of the custom blocks cells, as loaded last
 }
 
 type config struct {
-	Level        int           `json:"Level"`
-	Keys         []keymapEntry `json:"Keys"`
-	BlockColor   texture       `json:"blockcolor"`
-	BlockPattern texture       `json:"blockpattern"`
-	Scores       []scoreEntry  `json:"scores"`
+	Level        int                     `json:"Level"`
+	Keys         []keymapEntry           `json:"Keys"`
+	BlockColor   texture                 `json:"blockcolor"`
+	BlockPattern texture                 `json:"blockpattern"`
+	Randomizer   engine.Random           `json:"randomizer"`
+	Rotation     engine.RotationSystem   `json:"rotation"`
+	Ghost        bool                    `json:"ghost"`
+	DAS          int                     `json:"das"` // milliseconds
+	ARR          int                     `json:"arr"` // milliseconds
+	SoftDrop     int                     `json:"softdrop"`
+	LockDelay    int                     `json:"lockdelay"` // milliseconds
+	LockReset    engine.LockReset        `json:"lockreset"`
+	Previews     int                     `json:"previews"`
+	AllSpin      bool                    `json:"allspin"`
+	Scoring      engine.Scoring          `json:"scoring"`
+	Mode         engine.Mode             `json:"mode"`
+	UltraTime    int                     `json:"ultratime"` // seconds
+	CheeseLines  int                     `json:"cheeselines"`
+	Messiness    int                     `json:"messiness"` // percent
+	BoardCols    int                     `json:"boardcols"`
+	BoardRows    int                     `json:"boardrows"`
+	HiddenRows   int                     `json:"hiddenrows"`
+	Pieces       engine.PieceSet         `json:"pieces"`
+	Scores       []scoreEntry            `json:"scores"` // marathon scores
+	Boards       map[string][]scoreEntry `json:"boards"` // other scores by scoreKey
 }
 
 type themeArea struct { // app areas
@@ -118,6 +150,7 @@ func (ui *UI) Start(w *app.Window) (err error) {
 	*ui = UI{
 		theme:  th,
 		Config: "blocks.cfg",
+		Blocks: "blocks.txt",
 	}
 
 	ops := new(op.Ops)
@@ -127,6 +160,12 @@ func (ui *UI) Start(w *app.Window) (err error) {
 		case ev := <-evs:
 			switch e := ev.(type) {
 			case system.DestroyEvent:
+				if ui.state == uiGame {
+					// Keep the game to be resumed.
+					if err := ui.saveGame(); err != nil {
+						return err
+					}
+				}
 				return e.Err
 
 			case system.FrameEvent:
@@ -134,8 +173,11 @@ func (ui *UI) Start(w *app.Window) (err error) {
 				ui.Layout(gtx)
 				e.Frame(gtx.Ops)
 			}
-		case <-ui.game.Tick():
-			ui.game.Update(0)
+		case t := <-ui.game.Tick():
+			ui.game.Update(t)
+			w.Invalidate()
+		case t := <-ui.game.Tide():
+			ui.game.Update(t)
 			w.Invalidate()
 		}
 	}
@@ -143,7 +185,7 @@ func (ui *UI) Start(w *app.Window) (err error) {
 }
 
 func (ui *UI) saveConfig() (err error) {
-	dir, err := app.DataDir()
+	dir, err := dataDir()
 	if err != nil {
 		return err
 	}
@@ -181,7 +223,7 @@ func (ui *UI) saveConfig() (err error) {
 }
 
 func (ui *UI) loadConfig() (err error) {
-	dir, err := app.DataDir()
+	dir, err := dataDir()
 	if err != nil {
 		return err
 	}
@@ -269,11 +311,36 @@ func (ui *UI) init() {
 		Border:     ui.theme.Game.Border.Color,
 		Padding:    unit.Dp(6),
 		KeyMap:     ui.settings.Key,
+		KeyName:    ui.settings.KeyName,
+	}
+	ui.replays = replays{
+		Menu:    menu,
+		Padding: ui.theme.Area.Padding,
+		Game: game{
+			Menu:       menu,
+			ScoreLabel: label,
+			Label:      label,
+			Background: ui.theme.Game.Background,
+			Border:     ui.theme.Game.Border.Color,
+			Padding:    unit.Dp(6),
+		},
+	}
+
+	ui.demo = demo{
+		Game: game{
+			Menu:       menu,
+			ScoreLabel: label,
+			Label:      label,
+			Background: ui.theme.Game.Background,
+			Border:     ui.theme.Game.Border.Color,
+			Padding:    unit.Dp(6),
+		},
 	}
 
 	if err := ui.loadConfig(); err != nil {
 		ui.home.Error = err
 	}
+	ui.home.Resumable = hasSavedGame()
 }
 
 func (ui *UI) update() {
@@ -281,19 +348,53 @@ func (ui *UI) update() {
 	case uiHome:
 		level := ui.home.Level()
 		ui.game.StartLevel = level
-		ui.home.Title.Gravity = gameGravity(level)
+		ui.home.Title.Gravity = engine.Gravity(level)
 		ui.home.Title.Texture = ui.settings.Texture()
 		switch i := ui.home.Menu.Clicked(); i {
 		case homeStartGame:
 			ui.state = uiGame
-			ui.game.BlockTexture = ui.settings.Texture()
+			ui.setupGame()
+			ui.game.Shapes, ui.game.Colors = nil, nil
+			if ui.game.Pieces == engine.PiecesCustom {
+				shapes, err := ui.loadCustomBlocks(ui.game.Cols, ui.game.Rows, ui.game.Hidden)
+				if err != nil {
+					ui.state = uiHome
+					ui.home.Error = err
+					break
+				}
+				ui.game.Shapes = shapes
+				ui.game.Colors = ui.customBlocks
+			}
 			ui.game.Start()
+		case homeResumeGame:
+			ui.state = uiGame
+			ui.setupGame()
+			if err := ui.resumeGame(); err != nil {
+				ui.state = uiHome
+				ui.home.Error = err
+			}
 		case homeScoreBoard:
 			ui.state = uiScores
+			ui.scores.Kind = ui.settings.ScoreKind(ui.home.Mode())
+		case homeReplays:
+			ui.state = uiReplays
+			ui.home.Error = ui.replays.Load()
+			ui.replays.Game.BlockTexture = ui.settings.Texture()
+			ui.replays.Game.Ghost = ui.settings.Ghost()
+			ui.replays.Game.Colors = ui.customBlocks
 		case homeSettings:
 			ui.state = uiSettings
 		case homeQuitGame:
 			ui.state = uiQuit
+		default:
+			if ui.demo.Idle(time.Now()) {
+				ui.state = uiDemo
+				ui.startDemo()
+			}
+		}
+	case uiDemo:
+		if ui.demo.Stopped() {
+			ui.state = uiHome
 		}
 	case uiScores:
 		switch i := ui.scores.Menu.Clicked(); i {
@@ -302,18 +403,31 @@ func (ui *UI) update() {
 		}
 	case uiGame:
 		switch ui.game.state {
-		case gameOver:
+		case gameOver, gameTimeUp:
 			ui.state = uiGameOver
+			ui.home.Error = ui.saveReplay()
 		case gameLeft:
 			ui.state = uiHome
+			// The replay is saved once the resumed game ends,
+			// or when another game is saved instead.
+			ui.home.Error = ui.saveGame()
 		}
 	case uiGameOver:
 		if score, over := ui.game.Over(); over {
 			ui.state = uiHome
-			if ui.scores.NewScore(score) {
+			// Games played by the bot do not make it to the score board.
+			if !ui.game.Botted() && ui.scores.NewScore(ui.game.ScoreKind(), score, ui.game.Done()) {
 				ui.state = uiScores
 			}
 		}
+	case uiReplays:
+		switch i := ui.replays.Menu.Clicked(); i {
+		case replaysNewer, replaysOlder:
+			ui.replays.turn(i == replaysOlder)
+		case replaysBack:
+			ui.state = uiHome
+			ui.replays.Stop()
+		}
 	case uiSettings:
 		switch ui.settings.Menu.Clicked() {
 		case settingsBack:
@@ -321,6 +435,54 @@ func (ui *UI) update() {
 			ui.home.Error = ui.saveConfig()
 		}
 	}
+	if ui.state != uiHome {
+		ui.demo.Reset()
+	}
+}
+
+// setupGame sets the game with the player settings.
+func (ui *UI) setupGame() {
+	ui.game.BlockTexture = ui.settings.Texture()
+	ui.game.Randomizer = ui.settings.Randomizer()
+	ui.game.Rotation = ui.settings.Rotation()
+	ui.game.Ghost = ui.settings.Ghost()
+	ui.game.DAS, ui.game.ARR, ui.game.SoftDrop = ui.settings.AutoRepeat()
+	ui.game.LockDelay, ui.game.LockReset = ui.settings.Lock()
+	ui.game.Previews = ui.settings.Previews()
+	ui.game.AllSpin = ui.settings.AllSpin()
+	ui.game.Scoring = ui.settings.Scoring()
+	ui.game.Mode = ui.home.Mode()
+	ui.game.TimeLimit = ui.settings.UltraTime()
+	ui.game.Garbage, ui.game.Messiness = ui.settings.Garbage()
+	ui.game.Cols, ui.game.Rows, ui.game.Hidden = ui.settings.Board()
+	ui.game.Pieces = ui.settings.Pieces()
+}
+
+// startDemo starts the demo with the player settings.
+func (ui *UI) startDemo() {
+	g := &ui.demo.Game
+	g.StartLevel = ui.home.Level()
+	g.BlockTexture = ui.settings.Texture()
+	g.Randomizer = ui.settings.Randomizer()
+	g.Rotation = ui.settings.Rotation()
+	g.Ghost = ui.settings.Ghost()
+	g.Previews = ui.settings.Previews()
+	g.AllSpin = ui.settings.AllSpin()
+	g.Scoring = ui.settings.Scoring()
+	g.Cols, g.Rows, g.Hidden = ui.settings.Board()
+	g.Pieces = ui.settings.Pieces()
+	g.Shapes, g.Colors = nil, nil
+	if g.Pieces == engine.PiecesCustom {
+		// Invalid custom blocks are reported when starting a game,
+		// the demo using the standard ones meanwhile.
+		shapes, err := ui.loadCustomBlocks(g.Cols, g.Rows, g.Hidden)
+		if err != nil {
+			g.Pieces = engine.PiecesStandard
+		}
+		g.Shapes = shapes
+		g.Colors = ui.customBlocks
+	}
+	ui.demo.Start()
 }
 
 func (ui *UI) Layout(gtx layout.Context) layout.Dimensions {
@@ -333,13 +495,19 @@ func (ui *UI) Layout(gtx layout.Context) layout.Dimensions {
 	gtx.Constraints.Max.X = min(gtx.Constraints.Max.X, 1500)
 	switch ui.state {
 	case uiHome:
-		return ui.home.Layout(gtx)
+		dims := ui.home.Layout(gtx)
+		ui.demo.Watch(gtx)
+		return dims
+	case uiDemo:
+		return ui.demo.Layout(gtx)
 	case uiScores:
 		return ui.scores.Layout(gtx)
 	case uiGame, uiGameOver:
 		return ui.game.Layout(gtx)
 	case uiSettings:
 		return ui.settings.Layout(gtx)
+	case uiReplays:
+		return ui.replays.Layout(gtx)
 	}
 	return layout.Dimensions{}
 }